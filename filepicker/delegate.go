@@ -0,0 +1,221 @@
+package filepicker
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+const (
+	ellipsis          = "…"
+	defaultTimeFormat = "Jan _2 15:04"
+	sizeWidth         = 6
+)
+
+// Item is a file or directory in the file picker's list.
+type Item struct {
+	// Name is the base name of the file.
+	Name string
+
+	// Path is the slash-separated path of the file in the file system the
+	// picker browses.
+	Path string
+
+	// IsDir is whether the file is a directory, or a symbolic link to one.
+	IsDir bool
+
+	Size    int64
+	ModTime time.Time
+	Mode    fs.FileMode
+}
+
+// FilterValue is the value the list filters items by. It's part of the
+// list.Item interface.
+func (i Item) FilterValue() string {
+	return i.Name
+}
+
+// Styles contains style definitions for the file picker's delegate. By
+// default, these values are generated by DefaultStyles.
+type Styles struct {
+	// Cursor is the style of the marker in front of the selected item.
+	Cursor lipgloss.Style
+
+	File      lipgloss.Style
+	Directory lipgloss.Style
+	Selected  lipgloss.Style
+
+	// Size and ModTime are the styles of the size and modification time
+	// columns.
+	Size    lipgloss.Style
+	ModTime lipgloss.Style
+
+	// FilterMatch is applied to the parts of names matched by the list's
+	// filter or search.
+	FilterMatch lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for the file
+// picker's delegate.
+func DefaultStyles() (s Styles) {
+	subduedColor := lipgloss.AdaptiveColor{Light: "#9B9B9B", Dark: "#5C5C5C"}
+
+	s.Cursor = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#EE6FF8", Dark: "#EE6FF8"})
+
+	s.File = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#1a1a1a", Dark: "#dddddd"})
+
+	s.Directory = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.AdaptiveColor{Light: "#5A56E0", Dark: "#7571F9"})
+
+	s.Selected = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.AdaptiveColor{Light: "#EE6FF8", Dark: "#EE6FF8"})
+
+	s.Size = lipgloss.NewStyle().Foreground(subduedColor)
+	s.ModTime = lipgloss.NewStyle().Foreground(subduedColor)
+
+	s.FilterMatch = lipgloss.NewStyle().Underline(true)
+
+	return s
+}
+
+// Delegate renders the file picker's items on a single line each, with
+// directories marked by a trailing slash. The size and modification time of
+// each file are shown in columns on the right, which can be hidden by setting
+// ShowSize and ShowModTime to false.
+type Delegate struct {
+	Styles Styles
+
+	// CursorMarker is shown in front of the selected item.
+	CursorMarker string
+
+	ShowSize    bool
+	ShowModTime bool
+
+	// TimeFormat is the layout modification times are formatted with, as
+	// used by time.Time.Format.
+	TimeFormat string
+}
+
+// NewDelegate creates a new delegate with default styles.
+func NewDelegate() Delegate {
+	return Delegate{
+		Styles:       DefaultStyles(),
+		CursorMarker: ">",
+		ShowSize:     true,
+		ShowModTime:  true,
+		TimeFormat:   defaultTimeFormat,
+	}
+}
+
+// Height returns the height of an item, which is always 1.
+func (d Delegate) Height() int {
+	return 1
+}
+
+// Spacing returns the spacing between items, which is always 0.
+func (d Delegate) Spacing() int {
+	return 0
+}
+
+// Update does nothing. It's part of the list.ItemDelegate interface.
+func (d Delegate) Update(tea.Msg, *list.Model) tea.Cmd {
+	return nil
+}
+
+// Render prints an item.
+func (d Delegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	item, ok := listItem.(Item)
+	if !ok || m.Width() <= 0 {
+		return
+	}
+	s := &d.Styles
+
+	var (
+		isSelected = index == m.VisibleIndex() && m.FilterState() != list.Filtering
+		cursor     = lipgloss.NewStyle().Width(lipgloss.Width(d.CursorMarker) + 1).Render("")
+	)
+	if isSelected {
+		cursor = s.Cursor.Render(d.CursorMarker) + " "
+	}
+
+	// Columns on the right
+	var columns string
+	if d.ShowSize {
+		var size string
+		if !item.IsDir {
+			size = formatSize(item.Size)
+		}
+		columns += " " + s.Size.Render(fmt.Sprintf("%*s", sizeWidth, size))
+	}
+	if d.ShowModTime {
+		modTime := item.ModTime.Format(d.TimeFormat)
+		if item.ModTime.IsZero() {
+			modTime = strings.Repeat(" ", len(modTime))
+		}
+		columns += " " + s.ModTime.Render(modTime)
+	}
+
+	name := item.Name
+	if item.IsDir {
+		name += "/"
+	}
+	nameWidth := m.Width() - lipgloss.Width(cursor) - lipgloss.Width(columns)
+	name = truncate.StringWithTail(name, uint(max(0, nameWidth)), ellipsis)
+
+	style := s.File
+	if item.IsDir {
+		style = s.Directory
+	}
+	if isSelected {
+		style = s.Selected
+	}
+
+	// Highlight matches
+	var matchedRunes []int
+	if m.FilterState() != list.Unfiltered {
+		matchedRunes = append(matchedRunes, m.MatchesForItem(index)...)
+	}
+	if m.SearchState() != list.NotSearching {
+		matchedRunes = append(matchedRunes, m.SearchMatchesForItem(index)...)
+	}
+	if len(matchedRunes) > 0 {
+		name = lipgloss.StyleRunes(name, matchedRunes, style.Copy().Inherit(s.FilterMatch), style)
+	} else {
+		name = style.Render(name)
+	}
+
+	padding := max(0, nameWidth-lipgloss.Width(name))
+	fmt.Fprintf(w, "%s%s%*s%s", cursor, name, padding, "", columns)
+}
+
+// formatSize formats a file size in bytes for display, using binary prefixes.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
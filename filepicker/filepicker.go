@@ -0,0 +1,428 @@
+// Package filepicker provides a Bubble Tea component for choosing a file or
+// directory. It browses the directories of an fs.FS, such as os.DirFS or
+// fstest.MapFS, with a list.Model.
+package filepicker
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Internal ID management for file pickers. Directory reads are tagged with
+// the ID of the picker that started them so that pickers in the same program
+// don't pick up each other's reads.
+var (
+	lastID int
+	idMtx  sync.Mutex
+)
+
+// Return the next ID we should use on the Model.
+func nextID() int {
+	idMtx.Lock()
+	defer idMtx.Unlock()
+	lastID++
+	return lastID
+}
+
+// SelectionMode determines what can be chosen in the file picker.
+type SelectionMode int
+
+// Selection modes.
+const (
+	SelectFiles       SelectionMode = iota // files can be chosen
+	SelectDirectories                      // directories can be chosen, and files are hidden
+)
+
+// SelectedMsg is sent when the user chooses a file or directory.
+type SelectedMsg struct {
+	Item Item
+}
+
+// readDirMsg contains the entries of a directory read by the file picker with
+// the given ID. Once it's shown, the cursor is moved to the entry at focus,
+// if any.
+type readDirMsg struct {
+	id    int
+	gen   int
+	dir   string
+	focus string
+	items []Item
+	err   error
+}
+
+// KeyMap defines keybindings for navigating the file system. They're used
+// alongside the list's own keybindings.
+type KeyMap struct {
+	Open         key.Binding
+	Parent       key.Binding
+	Select       key.Binding
+	ToggleHidden key.Binding
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Open: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "open"),
+		),
+		Parent: key.NewBinding(
+			key.WithKeys("left", "h", "backspace"),
+			key.WithHelp("←/h", "parent"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+		ToggleHidden: key.NewBinding(
+			key.WithKeys("."),
+			key.WithHelp(".", "show hidden"),
+		),
+	}
+}
+
+// Model is the Bubble Tea model for this file picker element.
+type Model struct {
+	// List shows the entries of the current directory. Its keybindings for
+	// changing pages don't include the arrow keys and h and l, which open
+	// directories and go to the parent instead.
+	List   list.Model
+	KeyMap KeyMap
+
+	id    int
+	fsys  fs.FS
+	dir   string
+	items []Item
+
+	// The generation of the latest read that's been started, and of the one
+	// whose entries are shown.
+	gen      int
+	shownGen int
+
+	mode   SelectionMode
+	hidden bool
+	exts   []string
+}
+
+// New creates a new file picker that browses the root of the given file
+// system, with the given width and height.
+func New(fsys fs.FS, width, height int) Model {
+	l := list.New(nil, NewDelegate(), width, height)
+	l.SetStatusBarItemName("entry", "entries")
+	l.KeyMap.PrevPage = key.NewBinding(
+		key.WithKeys("pgup", "b", "u"),
+		key.WithHelp("b/pgup", "prev page"),
+	)
+	l.KeyMap.NextPage = key.NewBinding(
+		key.WithKeys("pgdown", "f", "d"),
+		key.WithHelp("f/pgdn", "next page"),
+	)
+
+	m := Model{
+		List:   l,
+		KeyMap: DefaultKeyMap(),
+		id:     nextID(),
+		fsys:   fsys,
+		dir:    ".",
+	}
+	m.List.Title = m.dir
+	m.updateHelp()
+	return m
+}
+
+// Init returns a command that reads the current directory. The read is
+// dropped if one started later, such as by SetDir, is shown first.
+func (m Model) Init() tea.Cmd {
+	return m.readDirCmd(m.gen, m.dir, "")
+}
+
+// Dir returns the slash-separated path of the current directory in the file
+// system, which is "." for its root.
+func (m Model) Dir() string {
+	return m.dir
+}
+
+// SetDir returns a command that reads the given directory, which becomes the
+// current directory once it's been read.
+func (m *Model) SetDir(dir string) tea.Cmd {
+	return m.readDir(path.Clean(dir), "")
+}
+
+// Parent returns a command that goes to the parent of the current directory,
+// with the cursor on the directory it came from.
+func (m *Model) Parent() tea.Cmd {
+	if m.dir == "." {
+		return nil
+	}
+	return m.readDir(path.Dir(m.dir), m.dir)
+}
+
+// SetSelectionMode sets whether files or directories can be chosen.
+func (m *Model) SetSelectionMode(mode SelectionMode) {
+	m.mode = mode
+	m.updateItems("")
+}
+
+// SelectionMode returns whether files or directories can be chosen.
+func (m Model) SelectionMode() SelectionMode {
+	return m.mode
+}
+
+// SetAllowedExtensions limits the files shown to those with the given
+// extensions, such as ".go" or "md", which are matched regardless of case.
+// With no extensions, all files are shown. Directories are always shown.
+func (m *Model) SetAllowedExtensions(exts ...string) {
+	m.exts = make([]string, len(exts))
+	for i, ext := range exts {
+		m.exts[i] = strings.ToLower(strings.TrimPrefix(ext, "."))
+	}
+	m.updateItems("")
+}
+
+// AllowedExtensions returns the extensions files are limited to, if any.
+func (m Model) AllowedExtensions() []string {
+	return m.exts
+}
+
+// SetShowHidden sets whether hidden files, whose names start with a dot, are
+// shown.
+func (m *Model) SetShowHidden(v bool) {
+	m.hidden = v
+	m.updateHelp()
+	m.updateItems("")
+}
+
+// ShowHidden returns whether hidden files are shown.
+func (m Model) ShowHidden() bool {
+	return m.hidden
+}
+
+// SelectedItem returns the entry under the cursor, if any.
+func (m Model) SelectedItem() (Item, bool) {
+	item, ok := m.List.SelectedItem().(Item)
+	return item, ok
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	m.updateHelp()
+
+	switch msg := msg.(type) {
+	case readDirMsg:
+		return m, m.handleReadDir(msg)
+
+	case tea.KeyMsg:
+		// Keys are typed into the list while it's filtering or searching.
+		if m.List.SettingFilter() || m.List.SearchState() == list.Searching {
+			break
+		}
+		if cmd, ok := m.handleKeys(msg); ok {
+			return m, cmd
+		}
+
+	case list.ItemActivatedMsg:
+		// Double-clicking an entry opens or chooses it.
+		if item, ok := msg.Item.(Item); ok && path.Dir(item.Path) == m.dir {
+			return m, m.activate(item)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.List, cmd = m.List.Update(msg)
+	return m, cmd
+}
+
+// handleKeys handles the file picker's own keybindings. It returns whether
+// the key was handled, or should be passed on to the list.
+func (m *Model) handleKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.KeyMap.Parent):
+		return m.Parent(), true
+
+	case key.Matches(msg, m.KeyMap.Open):
+		if item, ok := m.SelectedItem(); ok && item.IsDir {
+			return m.SetDir(item.Path), true
+		}
+		return nil, true
+
+	case key.Matches(msg, m.KeyMap.Select):
+		if item, ok := m.SelectedItem(); ok {
+			return m.activate(item), true
+		}
+		return nil, true
+
+	case key.Matches(msg, m.KeyMap.ToggleHidden):
+		m.SetShowHidden(!m.hidden)
+		return nil, true
+	}
+	return nil, false
+}
+
+// activate chooses the given entry if it can be chosen, or otherwise opens
+// it if it's a directory.
+func (m *Model) activate(item Item) tea.Cmd {
+	if item.IsDir == (m.mode == SelectDirectories) {
+		return func() tea.Msg {
+			return SelectedMsg{Item: item}
+		}
+	}
+	if item.IsDir {
+		return m.SetDir(item.Path)
+	}
+	return nil
+}
+
+// readDir returns a command that reads the given directory. Reads that finish
+// after a newer one has been shown are dropped.
+func (m *Model) readDir(dir, focus string) tea.Cmd {
+	m.gen++
+	return m.readDirCmd(m.gen, dir, focus)
+}
+
+// readDirCmd returns a command that reads the given directory as part of the
+// given generation of reads.
+func (m Model) readDirCmd(gen int, dir, focus string) tea.Cmd {
+	id, fsys := m.id, m.fsys
+	return func() tea.Msg {
+		items, err := readDir(fsys, dir)
+		return readDirMsg{id: id, gen: gen, dir: dir, focus: focus, items: items, err: err}
+	}
+}
+
+// handleReadDir makes a directory that's been read the current directory. A
+// read that fails leaves the current directory and its entries as they are,
+// and doesn't cause earlier reads that are still running to be dropped.
+func (m *Model) handleReadDir(msg readDirMsg) tea.Cmd {
+	if msg.id != m.id || msg.gen < m.shownGen {
+		return nil
+	}
+	if msg.err != nil {
+		return m.List.NewStatusMessage(fmt.Sprintf("Couldn't read %s: %v", msg.dir, msg.err))
+	}
+	m.shownGen = msg.gen
+
+	if msg.dir != m.dir {
+		m.List.ResetFilter()
+		m.List.Select(0)
+	}
+	m.dir = msg.dir
+	m.items = msg.items
+	m.List.Title = m.dir
+	m.updateItems(msg.focus)
+	return nil
+}
+
+// updateItems shows the entries of the current directory that pass the
+// picker's settings. The cursor stays on the selected entry, or moves to the
+// entry at the given path, if any.
+func (m *Model) updateItems(focus string) {
+	if focus == "" {
+		if item, ok := m.SelectedItem(); ok {
+			focus = item.Path
+		}
+	}
+
+	var items []list.Item
+	for _, item := range m.items {
+		if m.shown(item) {
+			items = append(items, item)
+		}
+	}
+	m.List.SetItems(items)
+
+	for i, item := range m.List.VisibleItems() {
+		if item.(Item).Path == focus {
+			m.List.Select(i)
+			break
+		}
+	}
+}
+
+// shown returns whether the given entry passes the picker's settings.
+func (m Model) shown(item Item) bool {
+	if !m.hidden && strings.HasPrefix(item.Name, ".") {
+		return false
+	}
+	if item.IsDir {
+		return true
+	}
+	if m.mode == SelectDirectories {
+		return false
+	}
+	if len(m.exts) == 0 {
+		return true
+	}
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(item.Name), "."))
+	for _, allowed := range m.exts {
+		if ext == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// updateHelp adds the picker's keybindings, as currently set, to the list's
+// help.
+func (m *Model) updateHelp() {
+	if m.hidden {
+		m.KeyMap.ToggleHidden.SetHelp(".", "hide hidden")
+	} else {
+		m.KeyMap.ToggleHidden.SetHelp(".", "show hidden")
+	}
+
+	keys := m.KeyMap
+	m.List.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Open, keys.Parent, keys.Select}
+	}
+	m.List.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Open, keys.Parent, keys.Select, keys.ToggleHidden}
+	}
+}
+
+// View renders the component.
+func (m Model) View() string {
+	return m.List.View()
+}
+
+// readDir reads the entries of a directory, with directories first. Symbolic
+// links to directories count as directories.
+func readDir(fsys fs.FS, dir string) ([]Item, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(entries))
+	for _, entry := range entries {
+		item := Item{
+			Name:  entry.Name(),
+			Path:  path.Join(dir, entry.Name()),
+			IsDir: entry.IsDir(),
+			Mode:  entry.Type(),
+		}
+		if info, err := entry.Info(); err == nil {
+			item.Size = info.Size()
+			item.ModTime = info.ModTime()
+			item.Mode = info.Mode()
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if info, err := fs.Stat(fsys, item.Path); err == nil {
+				item.IsDir = info.IsDir()
+			}
+		}
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].IsDir && !items[j].IsDir
+	})
+	return items, nil
+}
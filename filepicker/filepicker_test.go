@@ -0,0 +1,231 @@
+package filepicker
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var testFS = fstest.MapFS{
+	".hidden":         {},
+	"a.go":            {},
+	"b.MD":            {},
+	"docs/readme.md":  {},
+	"docs/guide.txt":  {},
+	"src/main.go":     {},
+	"src/.config/x":   {},
+	"src/lib/util.go": {},
+}
+
+func newTestPicker(t *testing.T) Model {
+	t.Helper()
+	m := New(testFS, 80, 40)
+	load(t, &m, m.Init())
+	return m
+}
+
+// load runs a command that reads a directory and passes the result to the
+// picker.
+func load(t *testing.T, m *Model, cmd tea.Cmd) {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("no command to read a directory")
+	}
+	msg, ok := cmd().(readDirMsg)
+	if !ok {
+		t.Fatalf("command didn't read a directory")
+	}
+	*m, _ = m.Update(msg)
+}
+
+// names returns the names of the entries shown.
+func names(m Model) []string {
+	var names []string
+	for _, item := range m.List.VisibleItems() {
+		names = append(names, item.(Item).Name)
+	}
+	return names
+}
+
+// selectName moves the cursor to the entry with the given name.
+func selectName(t *testing.T, m *Model, name string) {
+	t.Helper()
+	for i, n := range names(*m) {
+		if n == name {
+			m.List.Select(i)
+			return
+		}
+	}
+	t.Fatalf("%s isn't shown: %v", name, names(*m))
+}
+
+func keyMsg(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "left":
+		return tea.KeyMsg{Type: tea.KeyLeft}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func assertNames(t *testing.T, m Model, want ...string) {
+	t.Helper()
+	if got := names(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("entries = %v, want %v", got, want)
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	m := newTestPicker(t)
+	if m.Dir() != "." {
+		t.Errorf("Dir() = %q, want .", m.Dir())
+	}
+	assertNames(t, m, "docs", "src", "a.go", "b.MD")
+}
+
+func TestAllowedExtensions(t *testing.T) {
+	m := newTestPicker(t)
+
+	m.SetAllowedExtensions(".go")
+	assertNames(t, m, "docs", "src", "a.go")
+
+	m.SetAllowedExtensions("md", "txt")
+	assertNames(t, m, "docs", "src", "b.MD")
+
+	m.SetAllowedExtensions()
+	assertNames(t, m, "docs", "src", "a.go", "b.MD")
+}
+
+func TestToggleHidden(t *testing.T) {
+	m := newTestPicker(t)
+	selectName(t, &m, "b.MD")
+
+	m, _ = m.Update(keyMsg("."))
+	if !m.ShowHidden() {
+		t.Fatal("hidden files weren't shown")
+	}
+	assertNames(t, m, "docs", "src", ".hidden", "a.go", "b.MD")
+	if item, _ := m.SelectedItem(); item.Name != "b.MD" {
+		t.Errorf("selected %q after showing hidden files, want b.MD", item.Name)
+	}
+
+	m, _ = m.Update(keyMsg("."))
+	assertNames(t, m, "docs", "src", "a.go", "b.MD")
+
+	// Hidden directories are hidden too.
+	load(t, &m, m.SetDir("src"))
+	assertNames(t, m, "lib", "main.go")
+}
+
+func TestSelectFiles(t *testing.T) {
+	m := newTestPicker(t)
+
+	selectName(t, &m, "a.go")
+	_, cmd := m.Update(keyMsg("enter"))
+	if cmd == nil {
+		t.Fatal("choosing a file returned no command")
+	}
+	if msg, ok := cmd().(SelectedMsg); !ok || msg.Item.Path != "a.go" {
+		t.Errorf("choosing a.go sent %#v", msg)
+	}
+
+	// Directories are opened instead of chosen.
+	selectName(t, &m, "docs")
+	m, cmd = m.Update(keyMsg("enter"))
+	load(t, &m, cmd)
+	if m.Dir() != "docs" {
+		t.Errorf("Dir() = %q after choosing docs, want docs", m.Dir())
+	}
+	assertNames(t, m, "guide.txt", "readme.md")
+}
+
+func TestSelectDirectories(t *testing.T) {
+	m := newTestPicker(t)
+	m.SetSelectionMode(SelectDirectories)
+	assertNames(t, m, "docs", "src")
+
+	selectName(t, &m, "src")
+	_, cmd := m.Update(keyMsg("enter"))
+	if cmd == nil {
+		t.Fatal("choosing a directory returned no command")
+	}
+	if msg, ok := cmd().(SelectedMsg); !ok || msg.Item.Path != "src" {
+		t.Errorf("choosing src sent %#v", msg)
+	}
+
+	// Directories can still be opened.
+	m, cmd = m.Update(keyMsg("l"))
+	load(t, &m, cmd)
+	assertNames(t, m, "lib")
+}
+
+func TestParentFocus(t *testing.T) {
+	m := newTestPicker(t)
+	load(t, &m, m.SetDir("src/lib"))
+
+	m, cmd := m.Update(keyMsg("left"))
+	load(t, &m, cmd)
+	if m.Dir() != "src" {
+		t.Fatalf("Dir() = %q, want src", m.Dir())
+	}
+	if item, _ := m.SelectedItem(); item.Name != "lib" {
+		t.Errorf("selected %q in src, want lib", item.Name)
+	}
+
+	m, cmd = m.Update(keyMsg("left"))
+	load(t, &m, cmd)
+	if m.Dir() != "." {
+		t.Fatalf("Dir() = %q, want .", m.Dir())
+	}
+	if item, _ := m.SelectedItem(); item.Name != "src" {
+		t.Errorf("selected %q in the root, want src", item.Name)
+	}
+
+	if cmd := m.Parent(); cmd != nil {
+		t.Error("the root has a parent")
+	}
+}
+
+func TestSetDirError(t *testing.T) {
+	m := newTestPicker(t)
+
+	load(t, &m, m.SetDir("missing"))
+	if m.Dir() != "." {
+		t.Errorf("Dir() = %q after failing to read a directory, want .", m.Dir())
+	}
+	assertNames(t, m, "docs", "src", "a.go", "b.MD")
+	if m.List.StatusMessage() == "" {
+		t.Error("the error wasn't shown")
+	}
+}
+
+func TestSetDirErrorKeepsEarlierRead(t *testing.T) {
+	m := New(testFS, 80, 40)
+	initCmd := m.Init()
+
+	// Reads that fail don't cause the one started before them to be
+	// dropped.
+	load(t, &m, m.SetDir("missing"))
+	load(t, &m, m.SetDir("src/main.go"))
+	load(t, &m, initCmd)
+	if m.Dir() != "." {
+		t.Errorf("Dir() = %q, want .", m.Dir())
+	}
+	assertNames(t, m, "docs", "src", "a.go", "b.MD")
+}
+
+func TestStaleRead(t *testing.T) {
+	m := newTestPicker(t)
+	docsCmd := m.SetDir("docs")
+	srcCmd := m.SetDir("src")
+
+	load(t, &m, srcCmd)
+	load(t, &m, docsCmd)
+	if m.Dir() != "src" {
+		t.Errorf("Dir() = %q, want src", m.Dir())
+	}
+	assertNames(t, m, "lib", "main.go")
+}
@@ -0,0 +1,85 @@
+package textedit
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InitialBlinkMsg initializes cursor blinking.
+type InitialBlinkMsg struct{}
+
+// BlinkMsg signals that the cursor should blink. It contains metadata that
+// allows us to tell if the blink message is the one we're expecting.
+type BlinkMsg struct {
+	ID  int
+	Tag int
+}
+
+// BlinkCanceled is sent when a blink operation is canceled.
+type BlinkCanceled struct{}
+
+// Blink is a command used to initialize cursor blinking.
+func Blink() tea.Msg {
+	return InitialBlinkMsg{}
+}
+
+// blinkCtx manages cursor blinking.
+type blinkCtx struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Blinker times the cursor blinks of a component. Copies of a Blinker share
+// the pending blink, so that starting a new one cancels it.
+type Blinker struct {
+	// The ID of the component, as returned by NextID.
+	ID int
+
+	// The tag of the blink message we're expecting to receive.
+	tag int
+
+	ctx *blinkCtx
+}
+
+// NewBlinker creates a new Blinker for the component with the given ID.
+func NewBlinker(id int) Blinker {
+	return Blinker{
+		ID: id,
+		ctx: &blinkCtx{
+			ctx: context.Background(),
+		},
+	}
+}
+
+// Expects returns whether the given blink message is the one we're expecting.
+func (b Blinker) Expects(msg BlinkMsg) bool {
+	return msg.ID == b.ID && msg.Tag == b.tag
+}
+
+// Cmd returns a command that sends a BlinkMsg after the given delay,
+// canceling any blink that's pending.
+func (b *Blinker) Cmd(speed time.Duration) tea.Cmd {
+	if b.ctx == nil {
+		b.ctx = &blinkCtx{ctx: context.Background()}
+	}
+	if b.ctx.cancel != nil {
+		b.ctx.cancel()
+	}
+
+	ctx, cancel := context.WithTimeout(b.ctx.ctx, speed)
+	b.ctx.cancel = cancel
+
+	b.tag++
+	id, tag := b.ID, b.tag
+
+	return func() tea.Msg {
+		defer cancel()
+		<-ctx.Done()
+		if ctx.Err() == context.DeadlineExceeded {
+			return BlinkMsg{ID: id, Tag: tag}
+		}
+		return BlinkCanceled{}
+	}
+}
@@ -0,0 +1,103 @@
+package textedit
+
+import (
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// GraphemeBoundaries returns the indexes of the runes at which the grapheme
+// clusters in the given runes start, followed by the number of runes. The
+// cursor is only ever placed at one of these boundaries so that emoji
+// sequences, flags and characters with combining marks are edited as a
+// whole.
+func GraphemeBoundaries(runes []rune) []int {
+	bounds := []int{0}
+	n := 0
+	g := uniseg.NewGraphemes(string(runes))
+	for g.Next() {
+		n += len(g.Runes())
+		bounds = append(bounds, n)
+	}
+	return bounds
+}
+
+// BoundaryIndex returns the index of the given position in a list of
+// boundaries, or of the last boundary before it.
+func BoundaryIndex(bounds []int, pos int) int {
+	i := 0
+	for i+1 < len(bounds) && bounds[i+1] <= pos {
+		i++
+	}
+	return i
+}
+
+// PrevBoundary returns the start of the grapheme cluster before the given
+// position.
+func PrevBoundary(runes []rune, pos int) int {
+	prev := 0
+	for _, b := range GraphemeBoundaries(runes) {
+		if b >= pos {
+			break
+		}
+		prev = b
+	}
+	return prev
+}
+
+// NextBoundary returns the end of the grapheme cluster at the given position.
+func NextBoundary(runes []rune, pos int) int {
+	for _, b := range GraphemeBoundaries(runes) {
+		if b > pos {
+			return b
+		}
+	}
+	return len(runes)
+}
+
+// SnapToBoundary moves the given position back to the start of the grapheme
+// cluster it's in.
+func SnapToBoundary(runes []rune, pos int) int {
+	if pos <= 0 || pos >= len(runes) {
+		return pos
+	}
+	return PrevBoundary(runes, pos+1)
+}
+
+// WordLeft returns the start of the word before the given position, skipping
+// any whitespace in between. Words are separated by whitespace.
+func WordLeft(runes []rune, pos int) int {
+	bounds := GraphemeBoundaries(runes)
+	i := BoundaryIndex(bounds, pos)
+	for i > 0 && unicode.IsSpace(runes[bounds[i-1]]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(runes[bounds[i-1]]) {
+		i--
+	}
+	return bounds[i]
+}
+
+// WordRight returns the end of the word after the given position, skipping
+// any whitespace in between. Words are separated by whitespace.
+func WordRight(runes []rune, pos int) int {
+	bounds := GraphemeBoundaries(runes)
+	i := BoundaryIndex(bounds, pos)
+	for i+1 < len(bounds) && unicode.IsSpace(runes[bounds[i]]) {
+		i++
+	}
+	for i+1 < len(bounds) && !unicode.IsSpace(runes[bounds[i]]) {
+		i++
+	}
+	return bounds[i]
+}
+
+// FirstCluster splits the given string after its first grapheme cluster.
+func FirstCluster(s string) (string, string) {
+	g := uniseg.NewGraphemes(s)
+	if !g.Next() {
+		return "", ""
+	}
+	_, end := g.Positions()
+	return s[:end], s[end:]
+}
@@ -0,0 +1,41 @@
+// Package textedit contains the parts of text editing shared by the textinput
+// and textarea components: model IDs, cursor blinking, clipboard pastes and
+// grapheme-aware cursor motions.
+package textedit
+
+import (
+	"sync"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Internal ID management for text editing components. IDs are shared between
+// text inputs and text areas so that their blink messages can't be mixed up.
+var (
+	lastID int
+	idMtx  sync.Mutex
+)
+
+// NextID returns the next ID a text editing component should use.
+func NextID() int {
+	idMtx.Lock()
+	defer idMtx.Unlock()
+	lastID++
+	return lastID
+}
+
+// PasteMsg contains text pasted from the clipboard.
+type PasteMsg string
+
+// PasteErrMsg is sent when the clipboard can't be read.
+type PasteErrMsg struct{ error }
+
+// Paste is a command for pasting from the clipboard.
+func Paste() tea.Msg {
+	str, err := clipboard.ReadAll()
+	if err != nil {
+		return PasteErrMsg{err}
+	}
+	return PasteMsg(str)
+}
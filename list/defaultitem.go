@@ -75,6 +75,9 @@ type DefaultItem interface {
 // renders the list as single-line-items. The spacing between items can be set
 // with the SetSpacing method.
 //
+// In multi-select mode each item's title is prefixed with CheckedMarker or
+// UncheckedMarker, depending on whether the item is selected.
+//
 // Setting UpdateFunc is optional. If it's set it will be called when the
 // ItemDelegate called, which is called when the list's Update function is
 // invoked.
@@ -84,6 +87,8 @@ type DefaultItem interface {
 type DefaultDelegate struct {
 	ShowDescription bool
 	Styles          DefaultItemStyles
+	CheckedMarker   string
+	UncheckedMarker string
 	UpdateFunc      func(tea.Msg, *Model) tea.Cmd
 	ShortHelpFunc   func() []key.Binding
 	FullHelpFunc    func() [][]key.Binding
@@ -96,6 +101,8 @@ func NewDefaultDelegate() DefaultDelegate {
 	return DefaultDelegate{
 		ShowDescription: true,
 		Styles:          NewDefaultItemStyles(),
+		CheckedMarker:   "✓",
+		UncheckedMarker: bullet,
 		height:          2,
 		spacing:         1,
 	}
@@ -154,8 +161,19 @@ func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
 		return
 	}
 
+	// Checkmark for multi-select mode
+	var marker string
+	if m.MultiSelect() {
+		marker = d.UncheckedMarker
+		if m.IsSelected(m.ItemIndex(index)) {
+			marker = d.CheckedMarker
+		}
+		marker += " "
+	}
+	markerWidth := lipgloss.Width(marker)
+
 	// Prevent text from exceeding list width
-	textwidth := uint(m.width - s.NormalTitle.GetPaddingLeft() - s.NormalTitle.GetPaddingRight())
+	textwidth := uint(m.width - s.NormalTitle.GetPaddingLeft() - s.NormalTitle.GetPaddingRight() - markerWidth)
 	title = truncate.StringWithTail(title, textwidth, ellipsis)
 	if d.ShowDescription {
 		var lines []string
@@ -163,28 +181,39 @@ func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
 			if i >= d.height-1 {
 				break
 			}
-			lines = append(lines, truncate.StringWithTail(line, textwidth, ellipsis))
+			lines = append(lines, strings.Repeat(" ", markerWidth)+truncate.StringWithTail(line, textwidth, ellipsis))
 		}
 		desc = strings.Join(lines, "\n")
 	}
 
 	// Conditions
 	var (
-		isSelected  = index == m.Index()
+		isSelected  = index == m.VisibleIndex()
 		emptyFilter = m.FilterState() == Filtering && m.FilterValue() == ""
 		isFiltered  = m.FilterState() == Filtering || m.FilterState() == FilterApplied
+		isSearched  = m.SearchState() != NotSearching
+		highlight   = isFiltered || isSearched
 	)
 
+	// Get indices of matched characters, accounting for the checkmark
+	offset := len([]rune(marker))
 	if isFiltered && index < len(m.filteredItems) {
-		// Get indices of matched characters
-		matchedRunes = m.MatchesForItem(index)
+		for _, i := range m.MatchesForItem(index) {
+			matchedRunes = append(matchedRunes, i+offset)
+		}
+	}
+	if isSearched {
+		for _, i := range m.SearchMatchesForItem(index) {
+			matchedRunes = append(matchedRunes, i+offset)
+		}
 	}
+	title = marker + title
 
 	if emptyFilter {
 		title = s.DimmedTitle.Render(title)
 		desc = s.DimmedDesc.Render(desc)
 	} else if isSelected && m.FilterState() != Filtering {
-		if isFiltered {
+		if highlight {
 			// Highlight matches
 			unmatched := s.SelectedTitle.Inline(true)
 			matched := unmatched.Copy().Inherit(s.FilterMatch)
@@ -193,7 +222,7 @@ func (d DefaultDelegate) Render(w io.Writer, m Model, index int, item Item) {
 		title = s.SelectedTitle.Render(title)
 		desc = s.SelectedDesc.Render(desc)
 	} else {
-		if isFiltered {
+		if highlight {
 			// Highlight matches
 			unmatched := s.NormalTitle.Inline(true)
 			matched := unmatched.Copy().Inherit(s.FilterMatch)
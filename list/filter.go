@@ -0,0 +1,237 @@
+package list
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const defaultFilterBatchSize = 10000
+
+// Internal ID management for lists. Filter results are tagged with the ID of
+// the list that requested them so that lists in the same program don't pick
+// up each other's results.
+var (
+	lastID int
+	idMtx  sync.Mutex
+)
+
+// Return the next ID we should use on the Model.
+func nextID() int {
+	idMtx.Lock()
+	defer idMtx.Unlock()
+	lastID++
+	return lastID
+}
+
+// filterDebounceMsg is sent once the filter value has been left alone for the
+// debounce period.
+type filterDebounceMsg struct {
+	id  int
+	gen int64
+}
+
+// filterMatchesMsg contains a batch of items matched by the filter. It's
+// tagged with the generation of the filter request that produced it, so that
+// results for an outdated filter value can be dropped.
+type filterMatchesMsg struct {
+	id      int
+	gen     int64
+	matches filteredItems
+
+	// Whether the matches replace any previous results. That's the case for
+	// the first batch, and for the last one, which holds the matches from
+	// all batches ranked together.
+	replace bool
+
+	// Filters the next batch of items. Nil for the last batch.
+	next tea.Cmd
+}
+
+// filterJob filters the items in batches.
+type filterJob struct {
+	id      int
+	gen     int64
+	current *int64
+	mode    FilterMode
+	term    string
+	items   []Item
+	targets []string
+	size    int
+}
+
+// stale returns whether the filter has been requested again since this job
+// started, in which case its results are no longer needed.
+func (j filterJob) stale() bool {
+	return atomic.LoadInt64(j.current) != j.gen
+}
+
+// batch returns a command that filters a batch of items starting at the given
+// index, given the matches found in earlier batches. The resulting message
+// carries the command for the next batch.
+func (j filterJob) batch(start int, found filteredItems) tea.Cmd {
+	return func() tea.Msg {
+		if j.stale() {
+			return nil
+		}
+
+		end := len(j.items)
+		if j.size > 0 && start+j.size < end {
+			end = start + j.size
+		}
+
+		var ranks []Rank
+		if j.mode.ItemFilter != nil {
+			ranks = j.mode.ItemFilter(j.term, j.items[start:end])
+		} else {
+			ranks = j.mode.Filter(j.term, j.targets[start:end])
+		}
+
+		var matches filteredItems
+		for _, r := range ranks {
+			matches = append(matches, filteredItem{
+				index:   start + r.Index,
+				item:    j.items[start+r.Index],
+				matches: r.MatchedIndexes,
+			})
+		}
+
+		msg := filterMatchesMsg{
+			id:      j.id,
+			gen:     j.gen,
+			matches: matches,
+			replace: start == 0,
+		}
+		switch {
+		case end < len(j.items):
+			msg.next = j.batch(end, appendMatches(found, matches))
+		case start > 0:
+			// Each batch was ranked on its own, so once they're all in, the
+			// matches are ranked against each other.
+			msg.matches = j.rank(appendMatches(found, matches))
+			msg.replace = true
+		}
+		return msg
+	}
+}
+
+// rank filters the given matches again, all at once, so that they're in the
+// order the filter would have returned them in had it been called with all
+// of the items.
+func (j filterJob) rank(found filteredItems) filteredItems {
+	var ranks []Rank
+	if j.mode.ItemFilter != nil {
+		ranks = j.mode.ItemFilter(j.term, found.items())
+	} else {
+		targets := make([]string, len(found))
+		for i, f := range found {
+			targets[i] = j.targets[f.index]
+		}
+		ranks = j.mode.Filter(j.term, targets)
+	}
+
+	ranked := make(filteredItems, len(ranks))
+	for i, r := range ranks {
+		ranked[i] = found[r.Index]
+		ranked[i].matches = r.MatchedIndexes
+	}
+	return ranked
+}
+
+// filterItems requests that the items be filtered against the current filter
+// value, superseding any filtering that's underway. If debounce is true and
+// FilterDebounce is set, filtering starts once the filter value hasn't
+// changed for that long.
+func (m *Model) filterItems(debounce bool) tea.Cmd {
+	gen := atomic.AddInt64(m.filterGen, 1)
+
+	if debounce && m.FilterDebounce > 0 {
+		id := m.id
+		return tea.Tick(m.FilterDebounce, func(time.Time) tea.Msg {
+			return filterDebounceMsg{id: id, gen: gen}
+		})
+	}
+	return m.startFiltering(gen)
+}
+
+// startFiltering returns a command that filters the first batch of items for
+// the given filter request.
+func (m *Model) startFiltering(gen int64) tea.Cmd {
+	id := m.id
+
+	if m.FilterInput.Value() == "" || m.filterState == Unfiltered {
+		matches := m.itemsAsFilterItems()
+		return func() tea.Msg {
+			return filterMatchesMsg{id: id, gen: gen, matches: matches, replace: true}
+		}
+	}
+
+	// Targets are only collected when the items change rather than on every
+	// keystroke.
+	mode := m.FilterMode()
+	if mode.ItemFilter == nil && m.filterTargets == nil {
+		m.filterTargets = make([]string, len(m.items))
+		for i, item := range m.items {
+			m.filterTargets[i] = item.FilterValue()
+		}
+	}
+
+	// The job runs in the background, so it gets its own copy of the items,
+	// which are changed in place by SetItem and MoveItem. The targets are
+	// replaced rather than changed when the items change.
+	items := make([]Item, len(m.items))
+	copy(items, m.items)
+
+	job := filterJob{
+		id:      id,
+		gen:     gen,
+		current: m.filterGen,
+		mode:    mode,
+		term:    m.FilterInput.Value(),
+		items:   items,
+		targets: m.filterTargets,
+		size:    m.FilterBatchSize,
+	}
+	return job.batch(0, nil)
+}
+
+// handleFilterMatches applies a batch of filter results, unless they're for
+// an outdated filter request. Returns the command for the next batch, if any.
+func (m *Model) handleFilterMatches(msg filterMatchesMsg) tea.Cmd {
+	if msg.id != m.id || msg.gen != atomic.LoadInt64(m.filterGen) {
+		return nil
+	}
+
+	if msg.replace {
+		m.setFilteredItems(msg.matches)
+	} else {
+		m.setFilteredItems(appendMatches(m.rankedItems, msg.matches))
+	}
+
+	m.updatePagination()
+	if itemsOnPage := m.itemsOnPage(); m.cursor > itemsOnPage-1 {
+		m.cursor = max(0, itemsOnPage-1)
+	}
+	return msg.next
+}
+
+// invalidateFilterTargets discards the filter values collected from the items,
+// along with the search matches. It should be called whenever the items
+// change.
+func (m *Model) invalidateFilterTargets() {
+	m.filterTargets = nil
+	m.invalidateSearch()
+}
+
+// appendMatches adds the matches from a batch after those from earlier
+// batches. The matches in each batch stay in the order the filter returned
+// them in, so that custom filters can order matches as they see fit.
+// This returns a new slice, since the earlier matches may be shared with the
+// filtered items.
+func appendMatches(a, b filteredItems) filteredItems {
+	matches := make(filteredItems, 0, len(a)+len(b))
+	matches = append(matches, a...)
+	return append(matches, b...)
+}
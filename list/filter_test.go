@@ -0,0 +1,109 @@
+package list
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newFilterList(names ...string) Model {
+	items := make([]Item, len(names))
+	for i, name := range names {
+		items[i] = groupedItem{name: name}
+	}
+	return New(items, NewDefaultDelegate(), 40, 40)
+}
+
+// runFilter hands the results of a filter command to the list until the last
+// batch.
+func runFilter(m *Model, cmd tea.Cmd) {
+	for cmd != nil {
+		msg, ok := cmd().(filterMatchesMsg)
+		if !ok {
+			return
+		}
+		cmd = m.handleFilterMatches(msg)
+	}
+}
+
+func visibleNames(m Model) []string {
+	var names []string
+	for _, item := range m.VisibleItems() {
+		names = append(names, item.FilterValue())
+	}
+	return names
+}
+
+// startBatchedFilter filters the list by the given term in batches of two,
+// applying the first batch and returning the second one, which was filtered
+// before the caller changes the items.
+func startBatchedFilter(m *Model, term string) filterMatchesMsg {
+	m.FilterBatchSize = 2
+	m.FilterInput.SetValue(term)
+	m.filterState = FilterApplied
+	m.Filter = SubstringFilter
+	next := m.handleFilterMatches(m.filterItems(false)().(filterMatchesMsg))
+	return next().(filterMatchesMsg)
+}
+
+func TestRemoveItemWhileFiltering(t *testing.T) {
+	m := newFilterList("x1", "x2", "x3", "y", "x4")
+	pending := startBatchedFilter(&m, "x")
+
+	runFilter(&m, m.RemoveItem(0))
+	if cmd := m.handleFilterMatches(pending); cmd != nil {
+		t.Fatal("a batch filtered before the removal was applied")
+	}
+
+	if want := []string{"x2", "x3", "x4"}; !reflect.DeepEqual(visibleNames(m), want) {
+		t.Errorf("visible items = %v, want %v", visibleNames(m), want)
+	}
+	for i := range m.VisibleItems() {
+		if got, want := m.Items()[m.ItemIndex(i)], m.VisibleItems()[i]; got != want {
+			t.Errorf("Items()[ItemIndex(%d)] = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestMoveItemWhileFiltering(t *testing.T) {
+	m := newFilterList("x1", "x2", "x3", "y", "x4")
+	pending := startBatchedFilter(&m, "x")
+
+	// MoveItem batches its filter command with a ReorderedMsg, so the filter
+	// is run again here to finish it.
+	m.MoveItem(4, 0)
+	runFilter(&m, m.refilter())
+	if cmd := m.handleFilterMatches(pending); cmd != nil {
+		t.Fatal("a batch filtered before the move was applied")
+	}
+
+	if want := []string{"x4", "x1", "x2", "x3"}; !reflect.DeepEqual(visibleNames(m), want) {
+		t.Errorf("visible items = %v, want %v", visibleNames(m), want)
+	}
+}
+
+func TestBatchedFilterRanksAllMatches(t *testing.T) {
+	names := []string{"xaxxbxxc", "nothing", "xyz", "abc", "abc-close", "zzz"}
+	m := newFilterList(names...)
+	m.FilterBatchSize = 2
+	m.FilterInput.SetValue("abc")
+	m.filterState = FilterApplied
+	runFilter(&m, m.filterItems(false))
+
+	var want []string
+	for _, r := range DefaultFilter("abc", names) {
+		want = append(want, names[r.Index])
+	}
+	if want[0] != "abc" {
+		t.Fatalf("the test needs the best match in a later batch, got %v", want)
+	}
+	if got := visibleNames(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("visible items = %v, want %v, as ranked in one batch", got, want)
+	}
+	for i := range m.VisibleItems() {
+		if got, want := m.Items()[m.ItemIndex(i)], m.VisibleItems()[i]; got != want {
+			t.Errorf("Items()[ItemIndex(%d)] = %v, want %v", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package list
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ItemFilterFunc is like FilterFunc, but takes the items themselves rather
+// than their filter values. Use it for filters that need more than a single
+// string per item, such as FieldFilter.
+type ItemFilterFunc func(string, []Item) []Rank
+
+// FilterMode is a named filtering strategy. When the list has several filter
+// modes the user can cycle through them while filtering.
+type FilterMode struct {
+	// Name is shown in the filter prompt while the mode is active.
+	Name string
+
+	// Filter filters the items by their filter values.
+	Filter FilterFunc
+
+	// ItemFilter, if set, is used instead of Filter.
+	ItemFilter ItemFilterFunc
+}
+
+// DefaultFilterModes returns a set of filter modes using the filters in this
+// package, starting with fuzzy matching.
+func DefaultFilterModes() []FilterMode {
+	return []FilterMode{
+		{Name: "fuzzy", Filter: DefaultFilter},
+		{Name: "substring", Filter: SubstringFilter},
+		{Name: "prefix", Filter: PrefixFilter},
+		{Name: "case-sensitive", Filter: CaseSensitiveFilter},
+		{Name: "regex", Filter: RegexFilter},
+		{Name: "fields", ItemFilter: FieldFilter(SubstringFilter)},
+	}
+}
+
+// FieldFilterItem is an item with named fields that can be matched by
+// FieldFilter.
+type FieldFilterItem interface {
+	Item
+
+	// FilterFields returns the values of the item's fields by name.
+	FilterFields() map[string]string
+}
+
+// SubstringFilter matches items whose filter value contains the term,
+// ignoring case. Matches keep the order of the items.
+func SubstringFilter(term string, targets []string) []Rank {
+	return matchEach(targets, func(target string) (int, int, bool) {
+		return indexFold(target, term)
+	})
+}
+
+// PrefixFilter matches items whose filter value starts with the term,
+// ignoring case. Matches keep the order of the items.
+func PrefixFilter(term string, targets []string) []Rank {
+	return matchEach(targets, func(target string) (int, int, bool) {
+		start, end, ok := indexFold(target, term)
+		return start, end, ok && start == 0
+	})
+}
+
+// CaseSensitiveFilter matches items whose filter value contains the term,
+// including case. Matches keep the order of the items.
+func CaseSensitiveFilter(term string, targets []string) []Rank {
+	return matchEach(targets, func(target string) (int, int, bool) {
+		i := strings.Index(target, term)
+		return i, i + len(term), i >= 0
+	})
+}
+
+// ExactFilter matches items whose filter value is the term exactly, including
+// case. To match part of the filter value, use CaseSensitiveFilter. Matches
+// keep the order of the items.
+func ExactFilter(term string, targets []string) []Rank {
+	return matchEach(targets, func(target string) (int, int, bool) {
+		return 0, len(target), target == term
+	})
+}
+
+// RegexFilter matches items whose filter value matches the term as a regular
+// expression. While the term isn't a valid expression, for instance because
+// the user is still typing it, it's matched literally instead. Matches keep
+// the order of the items.
+func RegexFilter(term string, targets []string) []Rank {
+	re, err := regexp.Compile(term)
+	if err != nil {
+		re = regexp.MustCompile(regexp.QuoteMeta(term))
+	}
+	return matchEach(targets, func(target string) (int, int, bool) {
+		loc := re.FindStringIndex(target)
+		if loc == nil {
+			return 0, 0, false
+		}
+		return loc[0], loc[1], true
+	})
+}
+
+// FieldFilter returns a filter for terms made up of key:value tokens and free
+// text, such as "status:open author:ana crash". An item matches if it's a
+// FieldFilterItem whose field named key contains value, ignoring case, for
+// every key:value token, and if its filter value matches the remaining free
+// text with the given filter.
+func FieldFilter(filter FilterFunc) ItemFilterFunc {
+	return func(term string, items []Item) []Rank {
+		fields := make(map[string]string)
+		var text []string
+		for _, token := range strings.Fields(term) {
+			if i := strings.IndexRune(token, ':'); i > 0 {
+				fields[strings.ToLower(token[:i])] = token[i+1:]
+				continue
+			}
+			text = append(text, token)
+		}
+
+		var (
+			indexes []int
+			targets []string
+		)
+		for i, item := range items {
+			if matchFields(item, fields) {
+				indexes = append(indexes, i)
+				targets = append(targets, item.FilterValue())
+			}
+		}
+
+		if len(text) == 0 {
+			ranks := make([]Rank, len(indexes))
+			for i, index := range indexes {
+				ranks[i] = Rank{Index: index}
+			}
+			return ranks
+		}
+
+		ranks := filter(strings.Join(text, " "), targets)
+		for i := range ranks {
+			ranks[i].Index = indexes[ranks[i].Index]
+		}
+		return ranks
+	}
+}
+
+// matchFields returns whether the item's fields contain the given values.
+// Field names are compared ignoring case.
+func matchFields(item Item, fields map[string]string) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	fi, ok := item.(FieldFilterItem)
+	if !ok {
+		return false
+	}
+
+	values := make(map[string]string)
+	for k, v := range fi.FilterFields() {
+		values[strings.ToLower(k)] = v
+	}
+	for k, v := range fields {
+		value, ok := values[k]
+		if !ok {
+			return false
+		}
+		if _, _, ok := indexFold(value, v); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// matchEach runs a matching function over each target. The function returns
+// the byte range of the match, if any.
+func matchEach(targets []string, match func(string) (int, int, bool)) []Rank {
+	var ranks []Rank
+	for i, target := range targets {
+		start, end, ok := match(target)
+		if !ok {
+			continue
+		}
+		ranks = append(ranks, Rank{
+			Index:          i,
+			MatchedIndexes: runeIndexes(target, start, end),
+		})
+	}
+	return ranks
+}
+
+// indexFold returns the byte range of the first occurrence of substr in s,
+// ignoring case.
+func indexFold(s, substr string) (int, int, bool) {
+	n := utf8.RuneCountInString(substr)
+	for i := range s {
+		end, j := i, 0
+		for _, r := range s[i:] {
+			if j == n {
+				break
+			}
+			end += utf8.RuneLen(r)
+			j++
+		}
+		if j == n && strings.EqualFold(s[i:end], substr) {
+			return i, end, true
+		}
+	}
+	if n == 0 {
+		return 0, 0, true
+	}
+	return 0, 0, false
+}
+
+// runeIndexes returns the indexes of the runes within the given byte range of
+// s.
+func runeIndexes(s string, start, end int) []int {
+	var indexes []int
+	i := 0
+	for b := range s {
+		if b >= end {
+			break
+		}
+		if b >= start {
+			indexes = append(indexes, i)
+		}
+		i++
+	}
+	return indexes
+}
+
+// FilterMode returns the active filter mode. If the list has no filter modes
+// this is a mode using the list's Filter.
+func (m Model) FilterMode() FilterMode {
+	if len(m.FilterModes) == 0 {
+		return FilterMode{Filter: m.Filter}
+	}
+	return m.FilterModes[m.filterMode%len(m.FilterModes)]
+}
+
+// SetFilterMode activates the filter mode with the given name, if there is
+// one. This returns a command, which refilters the list if it's filtered.
+func (m *Model) SetFilterMode(name string) tea.Cmd {
+	for i, mode := range m.FilterModes {
+		if mode.Name == name {
+			m.filterMode = i
+			return m.refilter()
+		}
+	}
+	return nil
+}
+
+// NextFilterMode activates the next filter mode, wrapping around after the
+// last one. This returns a command, which refilters the list if it's
+// filtered.
+func (m *Model) NextFilterMode() tea.Cmd {
+	if len(m.FilterModes) == 0 {
+		return nil
+	}
+	m.filterMode = (m.filterMode + 1) % len(m.FilterModes)
+	return m.refilter()
+}
+
+// refilter filters the list again if a filter is set.
+func (m *Model) refilter() tea.Cmd {
+	if m.filterState == Unfiltered {
+		return nil
+	}
+	return m.filterItems(false)
+}
+
+// filterPrompt returns the filter prompt, including the name of the active
+// filter mode, if any.
+func (m Model) filterPrompt() string {
+	name := m.FilterMode().Name
+	prompt := m.FilterInput.Prompt
+	if name == "" {
+		return prompt
+	}
+	if strings.HasSuffix(prompt, ": ") {
+		return strings.TrimSuffix(prompt, ": ") + " (" + name + "): "
+	}
+	return prompt + "(" + name + ") "
+}
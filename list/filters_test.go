@@ -0,0 +1,51 @@
+package list
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExactFilter(t *testing.T) {
+	targets := []string{"Go", "go", "gopher", "ago", "go "}
+
+	ranks := ExactFilter("go", targets)
+	if len(ranks) != 1 || ranks[0].Index != 1 {
+		t.Fatalf("ExactFilter(go) = %v, want only the second target", ranks)
+	}
+	if want := []int{0, 1}; !reflect.DeepEqual(ranks[0].MatchedIndexes, want) {
+		t.Errorf("MatchedIndexes = %v, want %v", ranks[0].MatchedIndexes, want)
+	}
+
+	if ranks := ExactFilter("Go", targets); len(ranks) != 1 || ranks[0].Index != 0 {
+		t.Errorf("ExactFilter(Go) = %v, want only the first target", ranks)
+	}
+}
+
+func TestCaseSensitiveFilter(t *testing.T) {
+	targets := []string{"Gopher", "gopher", "a gopher hole", "GOPHER"}
+
+	ranks := CaseSensitiveFilter("goph", targets)
+	var got []int
+	for _, r := range ranks {
+		got = append(got, r.Index)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("CaseSensitiveFilter(goph) matched %v, want %v", got, want)
+	}
+	if want := []int{2, 3, 4, 5}; !reflect.DeepEqual(ranks[1].MatchedIndexes, want) {
+		t.Errorf("MatchedIndexes = %v, want %v", ranks[1].MatchedIndexes, want)
+	}
+}
+
+func TestDefaultFilterModesCaseSensitive(t *testing.T) {
+	for _, mode := range DefaultFilterModes() {
+		if mode.Name != "case-sensitive" {
+			continue
+		}
+		if ranks := mode.Filter("Go", []string{"Gopher", "gopher"}); len(ranks) != 1 || ranks[0].Index != 0 {
+			t.Errorf("case-sensitive mode matched %v, want only Gopher", ranks)
+		}
+		return
+	}
+	t.Error("DefaultFilterModes has no case-sensitive mode")
+}
@@ -0,0 +1,182 @@
+package list
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// headerHeight is the height of a group header.
+const headerHeight = 1
+
+// GroupedItem is an item that belongs to a named group, such as a date or a
+// category. Items in a list of grouped items are shown under a header for
+// each group. Headers can't be selected, and they don't count towards item
+// indexes.
+//
+// Items are kept together by group, with the groups in the order in which
+// they first appear. Items that don't implement GroupedItem, or whose group
+// is empty, are shown without a header.
+type GroupedItem interface {
+	Item
+
+	// Group returns the name of the group the item belongs to.
+	Group() string
+}
+
+// itemGroup returns the group of an item, if any.
+func itemGroup(item Item) string {
+	if g, ok := item.(GroupedItem); ok {
+		return g.Group()
+	}
+	return ""
+}
+
+// hasGroups returns whether any of the items belong to a group.
+func hasGroups(items []Item) bool {
+	for _, item := range items {
+		if itemGroup(item) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// groupOrder returns the indexes of the given groups, reordered so that
+// indexes in the same group are next to each other. Groups are ordered by
+// first appearance and indexes within a group keep their order.
+func groupOrder(groups []string) []int {
+	var (
+		names   []string
+		members = make(map[string][]int)
+	)
+	for i, g := range groups {
+		if _, ok := members[g]; !ok {
+			names = append(names, g)
+		}
+		members[g] = append(members[g], i)
+	}
+
+	order := make([]int, 0, len(groups))
+	for _, g := range names {
+		order = append(order, members[g]...)
+	}
+	return order
+}
+
+// groupFilteredItems returns the filter matches kept together by group.
+func groupFilteredItems(items filteredItems) filteredItems {
+	groups := make([]string, len(items))
+	for i, item := range items {
+		groups[i] = itemGroup(item.item)
+	}
+	grouped := make(filteredItems, len(items))
+	for i, j := range groupOrder(groups) {
+		grouped[i] = items[j]
+	}
+	return grouped
+}
+
+// paginateGroups splits the visible items into pages that fit the list's
+// height, including the group headers. Each page starts with the header of
+// its first item's group, so that headers stay in view while paging through
+// a group.
+func (m *Model) paginateGroups() {
+	items := m.VisibleItems()
+	m.pageStarts = []int{0}
+	for start := 0; ; {
+		end := m.fitItems(items, start)
+		if end >= len(items) {
+			break
+		}
+		m.pageStarts = append(m.pageStarts, end)
+		start = end
+	}
+	m.Paginator.TotalPages = len(m.pageStarts)
+}
+
+// fitItems returns the end of the range of items starting at start that fits
+// the list's height, including the header above the first item and any
+// headers where the group changes. At least one item always fits.
+func (m Model) fitItems(items []Item, start int) int {
+	rowHeight := m.delegate.Height() + m.delegate.Spacing()
+	budget := m.Paginator.PerPage * rowHeight
+
+	used := 0
+	for i := start; i < len(items); i++ {
+		cost := rowHeight
+		if group := itemGroup(items[i]); group != "" && (i == start || group != itemGroup(items[i-1])) {
+			cost += headerHeight
+		}
+		if i > start && used+cost > budget {
+			return i
+		}
+		used += cost
+	}
+	return len(items)
+}
+
+// pageBounds returns the range of visible items on the given page. In
+// scrolling mode this is the range of items in view.
+func (m Model) pageBounds(page int) (start, end int) {
+	items := m.VisibleItems()
+	numItems := len(items)
+	if m.scrolling {
+		start = min(m.offset, numItems)
+		return start, m.fitItems(items, start)
+	}
+	if m.pageStarts == nil {
+		return m.Paginator.GetSliceBounds(numItems)
+	}
+
+	page = max(0, min(page, len(m.pageStarts)-1))
+	start = min(m.pageStarts[page], numItems)
+	end = numItems
+	if page+1 < len(m.pageStarts) {
+		end = min(m.pageStarts[page+1], numItems)
+	}
+	return start, end
+}
+
+// itemsOnPage returns the number of visible items on the current page.
+func (m Model) itemsOnPage() int {
+	if m.pageStarts == nil && !m.scrolling {
+		return m.Paginator.ItemsOnPage(len(m.VisibleItems()))
+	}
+	start, end := m.pageBounds(m.Paginator.Page)
+	return end - start
+}
+
+// pageOf returns the page the visible item at the given index is on.
+func (m Model) pageOf(index int) int {
+	if m.pageStarts == nil {
+		return index / m.Paginator.PerPage
+	}
+	page := 0
+	for page+1 < len(m.pageStarts) && m.pageStarts[page+1] <= index {
+		page++
+	}
+	return page
+}
+
+// renderGroupedPage renders the items on the current page with a header
+// above the first item of each group. It returns the number of headers
+// rendered.
+func (m Model) renderGroupedPage(w io.Writer, items []Item, start int) int {
+	var (
+		headers int
+		style   = m.Styles.GroupHeader.Copy().MaxWidth(m.width)
+	)
+	for i, item := range items {
+		group := itemGroup(item)
+		if group != "" && (i == 0 || group != itemGroup(items[i-1])) {
+			fmt.Fprintln(w, style.Render(group))
+			headers++
+		}
+		m.renderItem(w, i+start, item)
+		if i != len(items)-1 {
+			fmt.Fprint(w, strings.Repeat("\n", m.delegate.Spacing()+1))
+		}
+	}
+	return headers
+}
@@ -0,0 +1,102 @@
+package list
+
+import "testing"
+
+type groupedItem struct {
+	name  string
+	group string
+}
+
+func (i groupedItem) FilterValue() string { return i.name }
+func (i groupedItem) Group() string       { return i.group }
+
+func newGroupedList() Model {
+	items := []Item{
+		groupedItem{"apple", "fruit"},
+		groupedItem{"carrot", "vegetable"},
+		groupedItem{"banana", "fruit"},
+		groupedItem{"leek", "vegetable"},
+		groupedItem{"cherry", "fruit"},
+	}
+	return New(items, NewDefaultDelegate(), 40, 40)
+}
+
+// applyFilter filters the list by the given term, running the filter to
+// completion.
+func applyFilter(m *Model, term string) {
+	m.FilterInput.SetValue(term)
+	m.filterState = FilterApplied
+	cmd := m.filterItems(false)
+	for cmd != nil {
+		msg, ok := cmd().(filterMatchesMsg)
+		if !ok {
+			break
+		}
+		cmd = m.handleFilterMatches(msg)
+	}
+}
+
+func TestGroupedIndex(t *testing.T) {
+	m := newGroupedList()
+
+	// Items are shown as apple, banana, cherry, carrot, leek.
+	for i := range m.VisibleItems() {
+		m.Select(i)
+		if m.VisibleIndex() != i {
+			t.Fatalf("VisibleIndex() = %d, want %d", m.VisibleIndex(), i)
+		}
+		if got, want := m.Items()[m.Index()], m.SelectedItem(); got != want {
+			t.Errorf("Items()[Index()] = %v, want selected item %v", got, want)
+		}
+	}
+
+	m.Select(3)
+	if m.Index() != 1 {
+		t.Errorf("Index() = %d, want 1", m.Index())
+	}
+}
+
+func TestGroupedIndexSorted(t *testing.T) {
+	m := newGroupedList()
+	m.SetSortOptions(SortByFilterValue)
+	m.SetSort(SortByFilterValue.Name)
+
+	for i := range m.VisibleItems() {
+		m.Select(i)
+		if got, want := m.Items()[m.Index()], m.SelectedItem(); got != want {
+			t.Errorf("Items()[Index()] = %v, want selected item %v", got, want)
+		}
+	}
+}
+
+func TestGroupedIndexFiltered(t *testing.T) {
+	m := newGroupedList()
+	applyFilter(&m, "e")
+	if len(m.VisibleItems()) == len(m.Items()) {
+		t.Fatalf("filter matched every item")
+	}
+
+	for i := range m.VisibleItems() {
+		m.Select(i)
+		if got, want := m.Items()[m.Index()], m.SelectedItem(); got != want {
+			t.Errorf("Items()[Index()] = %v, want selected item %v", got, want)
+		}
+	}
+}
+
+func TestGroupedRemoveItem(t *testing.T) {
+	m := newGroupedList()
+
+	// Select carrot, which is shown fourth but is the second item.
+	m.Select(3)
+	m.RemoveItem(m.Index())
+
+	for _, item := range m.Items() {
+		if item.(groupedItem).name == "carrot" {
+			t.Fatalf("carrot wasn't removed: %v", m.Items())
+		}
+	}
+	if len(m.Items()) != 4 {
+		t.Errorf("len(Items()) = %d, want 4", len(m.Items()))
+	}
+}
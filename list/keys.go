@@ -14,10 +14,38 @@ type KeyMap struct {
 	GoToEnd     key.Binding
 	Filter      key.Binding
 	ClearFilter key.Binding
+	CycleSort   key.Binding
+
+	// Keybindings used to move items when the list is reorderable.
+	MoveItemUp   key.Binding
+	MoveItemDown key.Binding
+
+	// Keybindings used in multi-select mode.
+	ToggleSelection key.Binding
+	SelectAll       key.Binding
+	SelectNone      key.Binding
+	InvertSelection key.Binding
 
 	// Keybindings used when setting a filter.
 	CancelWhileFiltering key.Binding
 	AcceptWhileFiltering key.Binding
+	CycleFilterMode      key.Binding
+
+	// Keybindings used when searching. Unlike filtering, searching keeps all
+	// items visible and moves between the matches. For vim-style searching
+	// bind Search to "/", which Filter is bound to by default. Search is only
+	// available while it doesn't share a key with an enabled Filter binding,
+	// such as while filtering is disabled.
+	Search       key.Binding
+	ClearSearch  key.Binding
+	NextMatch    key.Binding
+	PrevMatch    key.Binding
+	AcceptSearch key.Binding
+	CancelSearch key.Binding
+
+	// Keybinding used to move focus between the list and the preview pane
+	// while it's shown.
+	SwitchFocus key.Binding
 
 	// Help toggle keybindings.
 	ShowFullHelp  key.Binding
@@ -66,6 +94,38 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "clear filter"),
 		),
+		CycleSort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort"),
+		),
+
+		// Reordering.
+		MoveItemUp: key.NewBinding(
+			key.WithKeys("shift+up", "K"),
+			key.WithHelp("shift+↑/K", "move up"),
+		),
+		MoveItemDown: key.NewBinding(
+			key.WithKeys("shift+down", "J"),
+			key.WithHelp("shift+↓/J", "move down"),
+		),
+
+		// Multi-select.
+		ToggleSelection: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "toggle"),
+		),
+		SelectAll: key.NewBinding(
+			key.WithKeys("ctrl+a"),
+			key.WithHelp("ctrl+a", "select all"),
+		),
+		SelectNone: key.NewBinding(
+			key.WithKeys("ctrl+n"),
+			key.WithHelp("ctrl+n", "select none"),
+		),
+		InvertSelection: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "invert selection"),
+		),
 
 		// Filtering.
 		CancelWhileFiltering: key.NewBinding(
@@ -76,6 +136,42 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("enter", "tab", "shift+tab", "ctrl+k", "up", "ctrl+j", "down"),
 			key.WithHelp("enter", "apply filter"),
 		),
+		CycleFilterMode: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "filter mode"),
+		),
+
+		// Searching.
+		Search: key.NewBinding(
+			key.WithKeys("\\", "f3"),
+			key.WithHelp("\\", "search"),
+		),
+		ClearSearch: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear search"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next match"),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "prev match"),
+		),
+		AcceptSearch: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "search"),
+		),
+		CancelSearch: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+
+		// Preview pane.
+		SwitchFocus: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "switch focus"),
+		),
 
 		// Toggle help.
 		ShowFullHelp: key.NewBinding(
@@ -8,6 +8,7 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -15,6 +16,7 @@ import (
 	"github.com/charmbracelet/bubbles/paginator"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/reflow/ansi"
@@ -53,6 +55,7 @@ type ItemDelegate interface {
 }
 
 type filteredItem struct {
+	index   int   // index of the item in the list's items
 	item    Item  // item matched
 	matches []int // rune indices of matched items
 }
@@ -69,6 +72,10 @@ func (f filteredItems) items() []Item {
 
 // FilterMatchesMsg contains data about items matched during filtering. The
 // message should be routed to Update for processing.
+//
+// Deprecated: the list no longer produces this message. Filter results are
+// now tagged with the filter request they belong to so that outdated results
+// can be dropped.
 type FilterMatchesMsg []filteredItem
 
 // FilterFunc takes a term and a list of strings to search through
@@ -128,16 +135,95 @@ type Model struct {
 	showPagination   bool
 	showHelp         bool
 	filteringEnabled bool
+	multiSelect      bool
+	scrolling        bool
 
 	Title  string
 	Styles Styles
 
+	// TitleFunc, if set, renders the contents of the title bar in place of
+	// the title and status message. The filter input and spinner are still
+	// laid out around it.
+	TitleFunc func(m Model) string
+
+	// StatusSegmentsFunc, if set, returns the segments of the status bar,
+	// which are separated by Styles.DividerDot. DefaultStatusSegments
+	// returns the built-in segments.
+	StatusSegmentsFunc func(m Model) []string
+
+	// StatusStrings is the built-in text of the status bar, status messages
+	// and placeholders.
+	StatusStrings StatusStrings
+
+	// The names used for items in the status bar. See SetStatusBarItemName.
+	itemNameSingular string
+	itemNamePlural   string
+
 	// Key mappings for navigating the list.
 	KeyMap KeyMap
 
 	// Filter is used to filter the list.
 	Filter FilterFunc
 
+	// SearchFunc is used to find the items matching a search. Unlike Filter
+	// it's called with all of the visible items, and the order of the
+	// matches doesn't matter.
+	SearchFunc FilterFunc
+
+	// FilterModes, if set, are filtering strategies the user can cycle
+	// through while filtering. They take precedence over Filter. See
+	// DefaultFilterModes.
+	FilterModes []FilterMode
+
+	// FilterDebounce is how long to wait for the user to stop typing before
+	// filtering. This keeps typing responsive with very large lists. If 0 or
+	// less, the list is filtered on every keystroke.
+	FilterDebounce time.Duration
+
+	// FilterBatchSize is the number of items Filter is called with at once.
+	// Matches from each batch are shown as soon as they're found, after the
+	// matches from earlier batches and in the order Filter returned them in,
+	// which keeps very large lists responsive. Once the last batch is done,
+	// Filter is called again with all of the matches so that they're ranked
+	// against each other. Filtering is abandoned between batches if the
+	// filter changes. If 0 or less, all items are filtered in one go.
+	FilterBatchSize int
+
+	// LoadMore, if set, is called when the cursor reaches the last page of a
+	// list that has more items to load. The command it returns should load
+	// the next items and produce a MoreItemsMsg. Without it the list sends
+	// a LoadMoreMsg instead. See SetHasMore.
+	LoadMore func() tea.Cmd
+
+	// PreviewFunc provides the content of the preview pane for the selected
+	// item. See SetShowPreview.
+	PreviewFunc PreviewFunc
+
+	// Preview scrolls the content of the preview pane.
+	Preview viewport.Model
+
+	// MouseWheelEnabled sets whether the mouse wheel pages through the list,
+	// or scrolls it by MouseWheelDelta items in scrolling mode.
+	// MouseClickEnabled sets whether clicking an item selects it, and
+	// whether clicking a pagination dot goes to that page. Double-clicking
+	// an item sends an ItemActivatedMsg. Mouse events are expected to be
+	// relative to the top left corner of the list.
+	MouseWheelEnabled bool
+	MouseWheelDelta   int
+	MouseClickEnabled bool
+
+	// MouseDragEnabled sets whether items can be moved by dragging them with
+	// the mouse when the list is reorderable. See SetReorderable.
+	MouseDragEnabled bool
+
+	// FetchSize is the number of items fetched from the list's ItemSource at
+	// once. If 0 or less, a default size is used. See SetItemSource.
+	FetchSize int
+
+	// ScrollOff is the number of items kept in view above and below the
+	// cursor in scrolling mode, where possible. See SetScrolling.
+	ScrollOff int
+
 	disableQuitKeybindings bool
 
 	// Additional key mappings for the short and full help views. This allows
@@ -152,12 +238,36 @@ type Model struct {
 	showSpinner bool
 	width       int
 	height      int
+
+	// The width of the list and the preview pane together, the share of it
+	// taken by the preview pane, whether the pane is shown and has focus,
+	// and the index of the item whose content it shows, which is -1 if none
+	// is, along with the generation of that content.
+	fullWidth      int
+	previewRatio   float64
+	showPreview    bool
+	previewFocused bool
+	previewIndex   int
+	previewGen     int
+
 	Paginator   paginator.Model
 	cursor      int
 	Help        help.Model
 	FilterInput textinput.Model
 	filterState FilterState
 
+	// The search input, the search state, the index of the item the cursor
+	// was on when the search started, and the visible items matching the
+	// search, with the runes matched in each. The matches are for
+	// searchTerm, and are stale when the visible items have changed since.
+	SearchInput   textinput.Model
+	searchState   SearchState
+	searchOrigin  int
+	searchMatches []int
+	searchRunes   map[int][]int
+	searchTerm    string
+	searchStale   bool
+
 	// How long status messages should stay visible. By default this is
 	// 1 second.
 	StatusMessageLifetime time.Duration
@@ -173,7 +283,77 @@ type Model struct {
 	// this field should be considered ephemeral.
 	filteredItems filteredItems
 
+	// The items matching the filter in order of how well they match, before
+	// sorting.
+	rankedItems filteredItems
+
+	// Orders in which the user can choose to show the items and the index
+	// of the active one, where 0 is the order of the items themselves.
+	sortOptions []SortOption
+	sortIndex   int
+
+	// The items in the order they're shown when they're sorted or grouped,
+	// and the index of each in items. Nil when they're shown in their own
+	// order.
+	orderedItems   []Item
+	orderedIndexes []int
+
+	// The source backing the list, if any, its generation, which changes
+	// whenever the source is replaced, the chunks of items that have been
+	// requested from it, and those that failed to be fetched, which aren't
+	// requested again until the view scrolls away from fetchStart, the first
+	// item in view when they were last requested.
+	source      ItemSource
+	sourceGen   int64
+	fetched     map[int]bool
+	fetchFailed map[int]bool
+	fetchStart  int
+
+	// Whether there are more items to load on reaching the last page,
+	// whether they're being loaded, and the error loading them last failed
+	// with, if any, which holds off loading until the user moves down again.
+	hasMore     bool
+	loadingMore bool
+	loadMoreErr error
+
+	// The time and item of the last click, to detect double clicks, and
+	// whether the mouse button is held down.
+	lastClick      time.Time
+	lastClickIndex int
+	mouseDown      bool
+
+	// Whether the user can move items, and while an item is being dragged,
+	// where it started and where it is now.
+	reorderable bool
+	dragging    bool
+	dragFrom    int
+	dragIndex   int
+
+	// The index of the first item in view in scrolling mode.
+	offset int
+
+	// The index of the first visible item on each page when the items are
+	// grouped, in which case pages hold different numbers of items. Nil when
+	// the items aren't grouped.
+	pageStarts []int
+
+	// Items selected in multi-select mode, keyed by the item index or
+	// KeyedItem.SelectionKey.
+	selected map[interface{}]struct{}
+
 	delegate ItemDelegate
+
+	// The ID of this Model as it relates to other list Models.
+	id int
+
+	// The generation of the most recent filter request, shared with filtering
+	// that's underway so it can tell when it's been superseded, and the
+	// filter values of the items.
+	filterGen     *int64
+	filterTargets []string
+
+	// The index of the active filter mode.
+	filterMode int
 }
 
 // New returns a new model with sensible defaults.
@@ -191,6 +371,12 @@ func New(items []Item, delegate ItemDelegate, width, height int) Model {
 	filterInput.CharLimit = 64
 	filterInput.Focus()
 
+	searchInput := textinput.NewModel()
+	searchInput.Prompt = "Search: "
+	searchInput.PromptStyle = styles.FilterPrompt
+	searchInput.CursorStyle = styles.FilterCursor
+	searchInput.CharLimit = 64
+
 	p := paginator.NewModel()
 	p.Type = paginator.Dots
 	p.ActiveDot = styles.ActivePaginationDot.String()
@@ -207,18 +393,35 @@ func New(items []Item, delegate ItemDelegate, width, height int) Model {
 		Filter:                DefaultFilter,
 		Styles:                styles,
 		Title:                 "List",
+		itemNameSingular:      "item",
+		itemNamePlural:        "items",
+		StatusStrings:         DefaultStatusStrings(),
 		FilterInput:           filterInput,
+		SearchInput:           searchInput,
+		SearchFunc:            SubstringFilter,
+		FilterBatchSize:       defaultFilterBatchSize,
+		ScrollOff:             defaultScrollOff,
+		MouseWheelEnabled:     true,
+		MouseWheelDelta:       defaultMouseWheelDelta,
+		MouseClickEnabled:     true,
 		StatusMessageLifetime: time.Second,
+		Preview:               viewport.New(0, 0),
+		previewRatio:          defaultPreviewRatio,
+		previewIndex:          -1,
 
 		width:     width,
 		height:    height,
+		fullWidth: width,
 		delegate:  delegate,
 		items:     items,
 		Paginator: p,
 		spinner:   sp,
 		Help:      help.NewModel(),
+		id:        nextID(),
+		filterGen: new(int64),
 	}
 
+	m.sortItems()
 	m.updatePagination()
 	m.updateKeybindings()
 	return m
@@ -229,6 +432,19 @@ func New(items []Item, delegate ItemDelegate, width, height int) Model {
 // Deprecated. Use New instead.
 var NewModel = New
 
+// SetStatusBarItemName sets the singular and plural names used for items in
+// the status bar, such as "file" and "files".
+func (m *Model) SetStatusBarItemName(singular, plural string) {
+	m.itemNameSingular = singular
+	m.itemNamePlural = plural
+}
+
+// StatusBarItemName returns the singular and plural names used for items in
+// the status bar.
+func (m Model) StatusBarItemName() (string, string) {
+	return m.itemNameSingular, m.itemNamePlural
+}
+
 // SetFilteringEnabled enables or disables filtering. Note that this is different
 // from ShowFilter, which merely hides or shows the input view.
 func (m *Model) SetFilteringEnabled(v bool) {
@@ -317,11 +533,19 @@ func (m Model) Items() []Item {
 // Set the items available in the list. This returns a command.
 func (m *Model) SetItems(i []Item) tea.Cmd {
 	var cmd tea.Cmd
+	old := m.items
 	m.items = i
+	m.source = nil
+	m.fetched = nil
+	m.fetchFailed = nil
+	m.previewIndex = -1
+	m.carrySelection(old)
+	m.invalidateFilterTargets()
+	m.sortItems()
 
 	if m.filterState != Unfiltered {
-		m.filteredItems = nil
-		cmd = filterItems(*m)
+		m.setFilteredItems(nil)
+		cmd = m.filterItems(false)
 	}
 
 	m.updatePagination()
@@ -329,10 +553,27 @@ func (m *Model) SetItems(i []Item) tea.Cmd {
 	return cmd
 }
 
-// Select selects the given index of the list and goes to its respective page.
+// selectItemIndex selects the item at the given index in the entire slice of
+// items, if it's visible.
+func (m *Model) selectItemIndex(index int) {
+	for i := range m.VisibleItems() {
+		if m.ItemIndex(i) == index {
+			m.Select(i)
+			return
+		}
+	}
+}
+
+// Select selects the visible item at the given position, as returned by
+// VisibleIndex, and goes to its respective page.
 func (m *Model) Select(index int) {
-	m.Paginator.Page = index / m.Paginator.PerPage
-	m.cursor = index % m.Paginator.PerPage
+	if m.scrolling {
+		m.scrollTo(index)
+		return
+	}
+	m.Paginator.Page = m.pageOf(index)
+	start, _ := m.pageBounds(m.Paginator.Page)
+	m.cursor = index - start
 }
 
 // ResetSelected resets the selected item to the first item in the first page of the list.
@@ -349,9 +590,14 @@ func (m *Model) ResetFilter() {
 func (m *Model) SetItem(index int, item Item) tea.Cmd {
 	var cmd tea.Cmd
 	m.items[index] = item
+	if index == m.previewIndex {
+		m.previewIndex = -1
+	}
+	m.invalidateFilterTargets()
+	m.sortItems()
 
 	if m.filterState != Unfiltered {
-		cmd = filterItems(*m)
+		cmd = m.filterItems(false)
 	}
 
 	m.updatePagination()
@@ -362,10 +608,25 @@ func (m *Model) SetItem(index int, item Item) tea.Cmd {
 // item will be appended. This returns a command.
 func (m *Model) InsertItem(index int, item Item) tea.Cmd {
 	var cmd tea.Cmd
+	index = min(max(0, index), len(m.items))
 	m.items = insertItemIntoSlice(m.items, item, index)
+	shift := func(i int) int {
+		if i >= index {
+			return i + 1
+		}
+		return i
+	}
+	m.moveSelection(shift)
+	m.movePreview(shift)
+	m.invalidateFilterTargets()
+	m.sortItems()
 
 	if m.filterState != Unfiltered {
-		cmd = filterItems(*m)
+		// Keep the matches pointing at the right items until the filter has
+		// run again.
+		m.rankedItems = shiftFilterMatches(m.rankedItems, index, 1)
+		m.filteredItems = shiftFilterMatches(m.filteredItems, index, 1)
+		cmd = m.filterItems(false)
 	}
 
 	m.updatePagination()
@@ -375,16 +636,42 @@ func (m *Model) InsertItem(index int, item Item) tea.Cmd {
 
 // RemoveItem removes an item at the given index. If the index is out of bounds
 // this will be a no-op. O(n) complexity, which probably won't matter in the
-// case of a TUI.
-func (m *Model) RemoveItem(index int) {
+// case of a TUI. This returns a command, which filters the items again if the
+// list is filtered.
+func (m *Model) RemoveItem(index int) tea.Cmd {
+	if index < 0 || index >= len(m.items) {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.SetSelected(index, false)
 	m.items = removeItemFromSlice(m.items, index)
+	shift := func(i int) int {
+		switch {
+		case i == index:
+			return -1
+		case i > index:
+			return i - 1
+		}
+		return i
+	}
+	m.moveSelection(shift)
+	m.movePreview(shift)
+	m.invalidateFilterTargets()
+	m.sortItems()
 	if m.filterState != Unfiltered {
-		m.filteredItems = removeFilterMatchFromSlice(m.filteredItems, index)
+		// Keep the matches pointing at the right items until the filter has
+		// run again. Batches from a filter that's still running were matched
+		// against the items before the removal, so it's started over.
+		m.rankedItems = removeFilterMatch(m.rankedItems, index)
+		m.filteredItems = removeFilterMatch(m.filteredItems, index)
 		if len(m.filteredItems) == 0 {
 			m.resetFiltering()
+		} else {
+			cmd = m.filterItems(false)
 		}
 	}
 	m.updatePagination()
+	return cmd
 }
 
 // Set the item delegate.
@@ -398,12 +685,15 @@ func (m Model) VisibleItems() []Item {
 	if m.filterState != Unfiltered {
 		return m.filteredItems.items()
 	}
+	if m.orderedItems != nil {
+		return m.orderedItems
+	}
 	return m.items
 }
 
 // SelectedItems returns the current selected item in the list.
 func (m Model) SelectedItem() Item {
-	i := m.Index()
+	i := m.VisibleIndex()
 
 	items := m.VisibleItems()
 	if i < 0 || len(items) == 0 || len(items) <= i {
@@ -425,19 +715,52 @@ func (m Model) MatchesForItem(index int) []int {
 }
 
 // Index returns the index of the currently selected item as it appears in the
-// entire slice of items.
+// entire slice of items, as returned by Items. While the list is filtered,
+// sorted or grouped this differs from the item's position among the visible
+// items, which is returned by VisibleIndex.
 func (m Model) Index() int {
-	return m.Paginator.Page*m.Paginator.PerPage + m.cursor
+	return m.ItemIndex(m.VisibleIndex())
+}
+
+// VisibleIndex returns the position of the currently selected item among the
+// visible items, as returned by VisibleItems.
+func (m Model) VisibleIndex() int {
+	start, _ := m.pageBounds(m.Paginator.Page)
+	return start + m.cursor
+}
+
+// ItemIndex returns the index in the entire slice of items, as returned by
+// Items, of the visible item at the given position. Delegates are given
+// visible positions, which this maps to indexes for use with methods such as
+// IsSelected.
+func (m Model) ItemIndex(i int) int {
+	switch {
+	case m.filterState != Unfiltered:
+		if i >= 0 && i < len(m.filteredItems) {
+			return m.filteredItems[i].index
+		}
+	case m.orderedIndexes != nil:
+		if i >= 0 && i < len(m.orderedIndexes) {
+			return m.orderedIndexes[i]
+		}
+	}
+	return i
 }
 
-// Cursor returns the index of the cursor on the current page.
+// Cursor returns the index of the cursor on the current page. In scrolling
+// mode this is relative to the first item in view.
 func (m Model) Cursor() int {
 	return m.cursor
 }
 
 // CursorUp moves the cursor up. This can also move the state to the previous
-// page.
+// page, or scroll the view in scrolling mode.
 func (m *Model) CursorUp() {
+	if m.scrolling {
+		m.scrollTo(m.VisibleIndex() - 1)
+		return
+	}
+
 	m.cursor--
 
 	// If we're at the start, stop
@@ -453,13 +776,18 @@ func (m *Model) CursorUp() {
 
 	// Go to the previous page
 	m.Paginator.PrevPage()
-	m.cursor = m.Paginator.ItemsOnPage(len(m.VisibleItems())) - 1
+	m.cursor = m.itemsOnPage() - 1
 }
 
 // CursorDown moves the cursor down. This can also advance the state to the
-// next page.
+// next page, or scroll the view in scrolling mode.
 func (m *Model) CursorDown() {
-	itemsOnPage := m.Paginator.ItemsOnPage(len(m.VisibleItems()))
+	if m.scrolling {
+		m.scrollTo(m.VisibleIndex() + 1)
+		return
+	}
+
+	itemsOnPage := m.itemsOnPage()
 
 	m.cursor++
 
@@ -517,7 +845,8 @@ func (m Model) SettingFilter() bool {
 	return m.filterState == Filtering
 }
 
-// Width returns the current width setting.
+// Width returns the current width setting. While the preview pane is shown,
+// this is the width of the list beside it.
 func (m Model) Width() int {
 	return m.width
 }
@@ -577,6 +906,12 @@ func (m *Model) NewStatusMessage(s string) tea.Cmd {
 	}
 }
 
+// StatusMessage returns the status message currently showing, if any. This is
+// useful for showing it in a custom title bar. See TitleFunc.
+func (m Model) StatusMessage() string {
+	return m.statusMessage
+}
+
 // SetSize sets the width and height of this component.
 func (m *Model) SetSize(width, height int) {
 	m.setSize(width, height)
@@ -589,17 +924,13 @@ func (m *Model) SetWidth(v int) {
 
 // SetHeight sets the height of this component.
 func (m *Model) SetHeight(v int) {
-	m.setSize(m.width, v)
+	m.setSize(m.fullWidth, v)
 }
 
 func (m *Model) setSize(width, height int) {
-	promptWidth := lipgloss.Width(m.Styles.Title.Render(m.FilterInput.Prompt))
-
-	m.width = width
+	m.fullWidth = width
 	m.height = height
-	m.Help.Width = width
-	m.FilterInput.Width = width - promptWidth - lipgloss.Width(m.spinnerView())
-	m.updatePagination()
+	m.layout()
 }
 
 func (m *Model) resetFiltering() {
@@ -609,7 +940,8 @@ func (m *Model) resetFiltering() {
 
 	m.filterState = Unfiltered
 	m.FilterInput.Reset()
-	m.filteredItems = nil
+	m.setFilteredItems(nil)
+	atomic.AddInt64(m.filterGen, 1) // cancel any filtering that's underway
 	m.updatePagination()
 	m.updateKeybindings()
 }
@@ -618,7 +950,8 @@ func (m Model) itemsAsFilterItems() filteredItems {
 	fi := make([]filteredItem, len(m.items))
 	for i, item := range m.items {
 		fi[i] = filteredItem{
-			item: item,
+			index: i,
+			item:  item,
 		}
 	}
 	return filteredItems(fi)
@@ -626,8 +959,8 @@ func (m Model) itemsAsFilterItems() filteredItems {
 
 // Set keybindings according to the filter state.
 func (m *Model) updateKeybindings() {
-	switch m.filterState {
-	case Filtering:
+	switch {
+	case m.filterState == Filtering:
 		m.KeyMap.CursorUp.SetEnabled(false)
 		m.KeyMap.CursorDown.SetEnabled(false)
 		m.KeyMap.NextPage.SetEnabled(false)
@@ -636,8 +969,53 @@ func (m *Model) updateKeybindings() {
 		m.KeyMap.GoToEnd.SetEnabled(false)
 		m.KeyMap.Filter.SetEnabled(false)
 		m.KeyMap.ClearFilter.SetEnabled(false)
+		m.KeyMap.CycleSort.SetEnabled(false)
+		m.KeyMap.MoveItemUp.SetEnabled(false)
+		m.KeyMap.MoveItemDown.SetEnabled(false)
+		m.KeyMap.ToggleSelection.SetEnabled(false)
+		m.KeyMap.SelectAll.SetEnabled(false)
+		m.KeyMap.SelectNone.SetEnabled(false)
+		m.KeyMap.InvertSelection.SetEnabled(false)
 		m.KeyMap.CancelWhileFiltering.SetEnabled(true)
 		m.KeyMap.AcceptWhileFiltering.SetEnabled(m.FilterInput.Value() != "")
+		m.KeyMap.CycleFilterMode.SetEnabled(len(m.FilterModes) > 1)
+		m.KeyMap.Search.SetEnabled(false)
+		m.KeyMap.ClearSearch.SetEnabled(false)
+		m.KeyMap.NextMatch.SetEnabled(false)
+		m.KeyMap.PrevMatch.SetEnabled(false)
+		m.KeyMap.AcceptSearch.SetEnabled(false)
+		m.KeyMap.CancelSearch.SetEnabled(false)
+		m.KeyMap.SwitchFocus.SetEnabled(false)
+		m.KeyMap.Quit.SetEnabled(false)
+		m.KeyMap.ShowFullHelp.SetEnabled(false)
+		m.KeyMap.CloseFullHelp.SetEnabled(false)
+
+	case m.searchState == Searching:
+		m.KeyMap.CursorUp.SetEnabled(false)
+		m.KeyMap.CursorDown.SetEnabled(false)
+		m.KeyMap.NextPage.SetEnabled(false)
+		m.KeyMap.PrevPage.SetEnabled(false)
+		m.KeyMap.GoToStart.SetEnabled(false)
+		m.KeyMap.GoToEnd.SetEnabled(false)
+		m.KeyMap.Filter.SetEnabled(false)
+		m.KeyMap.ClearFilter.SetEnabled(false)
+		m.KeyMap.CycleSort.SetEnabled(false)
+		m.KeyMap.MoveItemUp.SetEnabled(false)
+		m.KeyMap.MoveItemDown.SetEnabled(false)
+		m.KeyMap.ToggleSelection.SetEnabled(false)
+		m.KeyMap.SelectAll.SetEnabled(false)
+		m.KeyMap.SelectNone.SetEnabled(false)
+		m.KeyMap.InvertSelection.SetEnabled(false)
+		m.KeyMap.CancelWhileFiltering.SetEnabled(false)
+		m.KeyMap.AcceptWhileFiltering.SetEnabled(false)
+		m.KeyMap.CycleFilterMode.SetEnabled(false)
+		m.KeyMap.Search.SetEnabled(false)
+		m.KeyMap.ClearSearch.SetEnabled(false)
+		m.KeyMap.NextMatch.SetEnabled(false)
+		m.KeyMap.PrevMatch.SetEnabled(false)
+		m.KeyMap.AcceptSearch.SetEnabled(true)
+		m.KeyMap.CancelSearch.SetEnabled(true)
+		m.KeyMap.SwitchFocus.SetEnabled(false)
 		m.KeyMap.Quit.SetEnabled(false)
 		m.KeyMap.ShowFullHelp.SetEnabled(false)
 		m.KeyMap.CloseFullHelp.SetEnabled(false)
@@ -647,17 +1025,43 @@ func (m *Model) updateKeybindings() {
 		m.KeyMap.CursorUp.SetEnabled(hasItems)
 		m.KeyMap.CursorDown.SetEnabled(hasItems)
 
-		hasPages := m.Paginator.TotalPages > 1
+		hasPages := m.Paginator.TotalPages > 1 || m.showScrollbar()
 		m.KeyMap.NextPage.SetEnabled(hasPages)
 		m.KeyMap.PrevPage.SetEnabled(hasPages)
 
 		m.KeyMap.GoToStart.SetEnabled(hasItems)
 		m.KeyMap.GoToEnd.SetEnabled(hasItems)
 
-		m.KeyMap.Filter.SetEnabled(m.filteringEnabled && hasItems)
+		m.KeyMap.Filter.SetEnabled(m.filteringEnabled && hasItems && m.source == nil)
 		m.KeyMap.ClearFilter.SetEnabled(m.filterState == FilterApplied)
+
+		m.KeyMap.CycleSort.SetEnabled(len(m.sortOptions) > 0 && hasItems && m.source == nil)
+
+		canReorder := m.canReorder() && len(m.items) > 1
+		m.KeyMap.MoveItemUp.SetEnabled(canReorder)
+		m.KeyMap.MoveItemDown.SetEnabled(canReorder)
+
+		canSelect := m.multiSelect && hasItems
+		m.KeyMap.ToggleSelection.SetEnabled(canSelect)
+		m.KeyMap.SelectAll.SetEnabled(canSelect)
+		m.KeyMap.SelectNone.SetEnabled(canSelect)
+		m.KeyMap.InvertSelection.SetEnabled(canSelect)
+
 		m.KeyMap.CancelWhileFiltering.SetEnabled(false)
 		m.KeyMap.AcceptWhileFiltering.SetEnabled(false)
+		m.KeyMap.CycleFilterMode.SetEnabled(false)
+
+		// Search is only available while it doesn't share a key with Filter.
+		hasMatches := m.searchState == SearchApplied && len(m.searchMatches) > 0
+		m.KeyMap.Search.SetEnabled(hasItems && !(m.KeyMap.Filter.Enabled() && sharesKeys(m.KeyMap.Search, m.KeyMap.Filter)))
+		m.KeyMap.ClearSearch.SetEnabled(m.searchState == SearchApplied)
+		m.KeyMap.NextMatch.SetEnabled(hasMatches)
+		m.KeyMap.PrevMatch.SetEnabled(hasMatches)
+		m.KeyMap.AcceptSearch.SetEnabled(false)
+		m.KeyMap.CancelSearch.SetEnabled(false)
+
+		m.KeyMap.SwitchFocus.SetEnabled(m.showPreview)
+
 		m.KeyMap.Quit.SetEnabled(!m.disableQuitKeybindings)
 
 		if m.Help.ShowAll {
@@ -673,7 +1077,7 @@ func (m *Model) updateKeybindings() {
 
 // Update pagination according to the amount of items for the current state.
 func (m *Model) updatePagination() {
-	index := m.Index()
+	index := m.VisibleIndex()
 	availHeight := m.height
 
 	if m.showTitle || (m.showFilter && m.filteringEnabled) {
@@ -691,15 +1095,22 @@ func (m *Model) updatePagination() {
 
 	m.Paginator.PerPage = max(1, availHeight/(m.delegate.Height()+m.delegate.Spacing()))
 
-	if pages := len(m.VisibleItems()); pages < 1 {
+	m.pageStarts = nil
+	if m.scrolling {
+		m.Paginator.SetTotalPages(1)
+		m.Paginator.Page = 0
+	} else if hasGroups(m.VisibleItems()) {
+		m.paginateGroups()
+	} else if pages := len(m.VisibleItems()); pages < 1 {
 		m.Paginator.SetTotalPages(1)
 	} else {
 		m.Paginator.SetTotalPages(pages)
 	}
 
 	// Restore index
-	m.Paginator.Page = index / m.Paginator.PerPage
-	m.cursor = index % m.Paginator.PerPage
+	m.Select(index)
+
+	m.updateSearch()
 
 	// Make sure the page stays in bounds
 	if m.Paginator.Page >= m.Paginator.TotalPages-1 {
@@ -725,9 +1136,36 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 
 	case FilterMatchesMsg:
-		m.filteredItems = filteredItems(msg)
+		m.setFilteredItems(filteredItems(msg))
+		return m, nil
+
+	case filterDebounceMsg:
+		if msg.id != m.id || msg.gen != atomic.LoadInt64(m.filterGen) {
+			return m, nil
+		}
+		return m, m.startFiltering(msg.gen)
+
+	case filterMatchesMsg:
+		return m, m.handleFilterMatches(msg)
+
+	case itemsFetchedMsg:
+		return m, m.handleItemsFetched(msg)
+
+	case moreItemsMsg:
+		return m, m.handleMoreItems(msg)
+
+	case previewMsg:
+		m.handlePreview(msg)
 		return m, nil
 
+	case tea.MouseMsg:
+		if m.inPreview(msg) {
+			return m, m.handlePreviewMouse(msg)
+		}
+		if msg.Type == tea.MouseLeft && m.previewFocused {
+			m.FocusList()
+		}
+
 	case spinner.TickMsg:
 		newSpinnerModel, cmd := m.spinner.Update(msg)
 		m.spinner = newSpinnerModel
@@ -741,9 +1179,14 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	if m.filterState == Filtering {
 		cmds = append(cmds, m.handleFiltering(msg))
+	} else if m.searchState == Searching {
+		cmds = append(cmds, m.handleSearching(msg))
+	} else if m.previewFocused {
+		cmds = append(cmds, m.handlePreviewFocused(msg))
 	} else {
 		cmds = append(cmds, m.handleBrowsing(msg))
 	}
+	cmds = append(cmds, m.fetchItems(), m.loadMore(), m.updatePreview())
 
 	return m, tea.Batch(cmds...)
 }
@@ -751,13 +1194,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 // Updates for when a user is browsing the list.
 func (m *Model) handleBrowsing(msg tea.Msg) tea.Cmd {
 	var cmds []tea.Cmd
-	numItems := len(m.VisibleItems())
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
-		// Note: we match clear filter before quit because, by default, they're
-		// both mapped to escape.
+		// Note: we match clear search and clear filter before quit because,
+		// by default, they're all mapped to escape.
+		case key.Matches(msg, m.KeyMap.ClearSearch):
+			m.ClearSearch()
+
 		case key.Matches(msg, m.KeyMap.ClearFilter):
 			m.resetFiltering()
 
@@ -768,49 +1213,93 @@ func (m *Model) handleBrowsing(msg tea.Msg) tea.Cmd {
 			m.CursorUp()
 
 		case key.Matches(msg, m.KeyMap.CursorDown):
+			m.retryLoadMore()
 			m.CursorDown()
 
 		case key.Matches(msg, m.KeyMap.PrevPage):
-			m.Paginator.PrevPage()
+			if m.scrolling {
+				m.scrollTo(m.VisibleIndex() - m.itemsOnPage())
+			} else {
+				m.Paginator.PrevPage()
+			}
 
 		case key.Matches(msg, m.KeyMap.NextPage):
-			m.Paginator.NextPage()
+			m.retryLoadMore()
+			if m.scrolling {
+				m.scrollTo(m.VisibleIndex() + m.itemsOnPage())
+			} else {
+				m.Paginator.NextPage()
+			}
 
 		case key.Matches(msg, m.KeyMap.GoToStart):
-			m.Paginator.Page = 0
-			m.cursor = 0
+			m.Select(0)
 
 		case key.Matches(msg, m.KeyMap.GoToEnd):
-			m.Paginator.Page = m.Paginator.TotalPages - 1
-			m.cursor = m.Paginator.ItemsOnPage(numItems) - 1
+			m.retryLoadMore()
+			m.Select(len(m.VisibleItems()) - 1)
+
+		case key.Matches(msg, m.KeyMap.CycleSort):
+			m.NextSort()
+
+		case key.Matches(msg, m.KeyMap.MoveItemUp):
+			cmds = append(cmds, m.moveSelectedItem(-1))
+
+		case key.Matches(msg, m.KeyMap.MoveItemDown):
+			cmds = append(cmds, m.moveSelectedItem(1))
+
+		case key.Matches(msg, m.KeyMap.Search):
+			return m.StartSearch()
+
+		case key.Matches(msg, m.KeyMap.NextMatch):
+			m.NextMatch()
+
+		case key.Matches(msg, m.KeyMap.PrevMatch):
+			m.PrevMatch()
+
+		case key.Matches(msg, m.KeyMap.ToggleSelection):
+			m.ToggleSelection()
+
+		case key.Matches(msg, m.KeyMap.SelectAll):
+			m.SelectAll()
+
+		case key.Matches(msg, m.KeyMap.SelectNone):
+			m.SelectNone()
+
+		case key.Matches(msg, m.KeyMap.InvertSelection):
+			m.InvertSelection()
 
 		case key.Matches(msg, m.KeyMap.Filter):
 			m.hideStatusMessage()
 			if m.FilterInput.Value() == "" {
 				// Populate filter with all items only if the filter is empty.
-				m.filteredItems = m.itemsAsFilterItems()
+				m.setFilteredItems(m.itemsAsFilterItems())
 			}
-			m.Paginator.Page = 0
-			m.cursor = 0
+			m.Select(0)
 			m.filterState = Filtering
 			m.FilterInput.CursorEnd()
 			m.FilterInput.Focus()
 			m.updateKeybindings()
 			return textinput.Blink
 
+		case key.Matches(msg, m.KeyMap.SwitchFocus):
+			m.FocusPreview()
+
 		case key.Matches(msg, m.KeyMap.ShowFullHelp):
 			fallthrough
 		case key.Matches(msg, m.KeyMap.CloseFullHelp):
 			m.Help.ShowAll = !m.Help.ShowAll
 			m.updatePagination()
 		}
+
+	case tea.MouseMsg:
+		cmds = append(cmds, m.handleMouse(msg))
 	}
 
 	cmd := m.delegate.Update(msg, m)
 	cmds = append(cmds, cmd)
 
 	// Keep the index in bounds when paginating
-	itemsOnPage := m.Paginator.ItemsOnPage(len(m.VisibleItems()))
+	itemsOnPage := m.itemsOnPage()
 	if m.cursor > itemsOnPage-1 {
 		m.cursor = max(0, itemsOnPage-1)
 	}
@@ -852,6 +1341,11 @@ func (m *Model) handleFiltering(msg tea.Msg) tea.Cmd {
 			if m.FilterInput.Value() == "" {
 				m.resetFiltering()
 			}
+
+		case key.Matches(msg, m.KeyMap.CycleFilterMode):
+			cmd := m.NextFilterMode()
+			m.updatePagination()
+			return cmd
 		}
 	}
 
@@ -863,7 +1357,7 @@ func (m *Model) handleFiltering(msg tea.Msg) tea.Cmd {
 
 	// If the filtering input has changed, request updated filtering
 	if filterChanged {
-		cmds = append(cmds, filterItems(*m))
+		cmds = append(cmds, m.filterItems(true))
 		m.KeyMap.AcceptWhileFiltering.SetEnabled(m.FilterInput.Value() != "")
 	}
 
@@ -876,9 +1370,14 @@ func (m *Model) handleFiltering(msg tea.Msg) tea.Cmd {
 // ShortHelp returns bindings to show in the abbreviated help view. It's part
 // of the help.KeyMap interface.
 func (m Model) ShortHelp() []key.Binding {
+	if m.previewFocused {
+		return m.previewHelp()
+	}
+
 	kb := []key.Binding{
 		m.KeyMap.CursorUp,
 		m.KeyMap.CursorDown,
+		m.KeyMap.ToggleSelection,
 	}
 
 	filtering := m.filterState == Filtering
@@ -896,6 +1395,11 @@ func (m Model) ShortHelp() []key.Binding {
 		m.KeyMap.ClearFilter,
 		m.KeyMap.AcceptWhileFiltering,
 		m.KeyMap.CancelWhileFiltering,
+		m.KeyMap.CycleFilterMode,
+		m.KeyMap.NextMatch,
+		m.KeyMap.ClearSearch,
+		m.KeyMap.AcceptSearch,
+		m.KeyMap.CancelSearch,
 	)
 
 	if !filtering && m.AdditionalShortHelpKeys != nil {
@@ -903,6 +1407,7 @@ func (m Model) ShortHelp() []key.Binding {
 	}
 
 	return append(kb,
+		m.KeyMap.SwitchFocus,
 		m.KeyMap.Quit,
 		m.KeyMap.ShowFullHelp,
 	)
@@ -911,6 +1416,10 @@ func (m Model) ShortHelp() []key.Binding {
 // FullHelp returns bindings to show the full help view. It's part of the
 // help.KeyMap interface.
 func (m Model) FullHelp() [][]key.Binding {
+	if m.previewFocused {
+		return [][]key.Binding{m.previewHelp()}
+	}
+
 	kb := [][]key.Binding{{
 		m.KeyMap.CursorUp,
 		m.KeyMap.CursorDown,
@@ -930,11 +1439,31 @@ func (m Model) FullHelp() [][]key.Binding {
 		}
 	}
 
+	if m.multiSelect && !filtering {
+		kb = append(kb, []key.Binding{
+			m.KeyMap.ToggleSelection,
+			m.KeyMap.SelectAll,
+			m.KeyMap.SelectNone,
+			m.KeyMap.InvertSelection,
+		})
+	}
+
 	listLevelBindings := []key.Binding{
 		m.KeyMap.Filter,
 		m.KeyMap.ClearFilter,
+		m.KeyMap.CycleSort,
+		m.KeyMap.MoveItemUp,
+		m.KeyMap.MoveItemDown,
 		m.KeyMap.AcceptWhileFiltering,
 		m.KeyMap.CancelWhileFiltering,
+		m.KeyMap.CycleFilterMode,
+		m.KeyMap.Search,
+		m.KeyMap.ClearSearch,
+		m.KeyMap.NextMatch,
+		m.KeyMap.PrevMatch,
+		m.KeyMap.AcceptSearch,
+		m.KeyMap.CancelSearch,
+		m.KeyMap.SwitchFocus,
 	}
 
 	if !filtering && m.AdditionalFullHelpKeys != nil {
@@ -991,7 +1520,14 @@ func (m Model) View() string {
 		sections = append(sections, help)
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	view := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	if m.showPreview {
+		// Keep the list to its share of the width so the preview pane lines
+		// up beside it.
+		view = lipgloss.PlaceHorizontal(m.width, lipgloss.Left, lipgloss.NewStyle().MaxWidth(m.width).Render(view))
+		view = lipgloss.JoinHorizontal(lipgloss.Top, view, m.previewView())
+	}
+	return view
 }
 
 func (m Model) titleView() string {
@@ -1009,7 +1545,13 @@ func (m Model) titleView() string {
 
 	// If the filter's showing, draw that. Otherwise draw the title.
 	if m.showFilter && m.filterState == Filtering {
-		view += m.FilterInput.View()
+		// Show the active filter mode in the prompt
+		input := m.FilterInput
+		input.Prompt = m.filterPrompt()
+		input.Width -= lipgloss.Width(input.Prompt) - lipgloss.Width(m.FilterInput.Prompt)
+		view += input.View()
+	} else if m.searchState == Searching {
+		view += m.SearchInput.View()
 	} else if m.showTitle {
 		if m.showSpinner && spinnerOnLeft {
 			view += spinnerView + spinnerLeftGap
@@ -1017,11 +1559,17 @@ func (m Model) titleView() string {
 			titleBarStyle = titleBarStyle.PaddingLeft(titleBarGap - spinnerWidth - lipgloss.Width(spinnerLeftGap))
 		}
 
-		view += m.Styles.Title.Render(m.Title)
+		if m.TitleFunc != nil {
+			view += m.TitleFunc(m)
+		} else {
+			view += m.Styles.Title.Render(m.Title)
 
-		// Status message
+			// Status message
+			if m.filterState != Filtering {
+				view += "  " + m.statusMessage
+			}
+		}
 		if m.filterState != Filtering {
-			view += "  " + m.statusMessage
 			view = truncate.StringWithTail(view, uint(m.width-spinnerWidth), ellipsis)
 		}
 	}
@@ -1043,26 +1591,96 @@ func (m Model) titleView() string {
 }
 
 func (m Model) statusView() string {
-	var status string
-
-	totalItems := len(m.items)
-	visibleItems := len(m.VisibleItems())
-
-	plural := ""
-	if visibleItems != 1 {
-		plural = "s"
-	}
+	var segments []string
+	if m.StatusSegmentsFunc != nil {
+		segments = m.StatusSegmentsFunc(m)
+	} else {
+		segments = m.DefaultStatusSegments()
+	}
+	return m.Styles.StatusBar.Render(strings.Join(segments, m.Styles.DividerDot.String()))
+}
+
+// StatusStrings contains the text the list shows in the status bar, in status
+// messages and in place of items that are missing or haven't loaded, so that
+// it can be changed or translated. Strings with verbs are formatted with fmt.
+// By default, these values are generated by DefaultStatusStrings.
+type StatusStrings struct {
+	// NoItems is shown in the status bar when the list is empty, given the
+	// plural item name. NoItemsFound is shown in place of the items.
+	NoItems      string
+	NoItemsFound string
+
+	// NothingMatched is shown in the status bar while the filter matches no
+	// items.
+	NothingMatched string
+
+	// Filtered, SortedBy and Selected are given the number of items
+	// filtered out, the name of the active sort option and the number of
+	// selected items.
+	Filtered string
+	SortedBy string
+	Selected string
+
+	// NoSearchMatches is shown when the search matches no items.
+	// SearchMatch and SearchMatches are given the number of matches, and
+	// SearchPosition the number of the match under the cursor followed by
+	// the number of matches.
+	NoSearchMatches string
+	SearchMatch     string
+	SearchMatches   string
+	SearchPosition  string
+
+	// Loading is shown in place of items that haven't been fetched from the
+	// list's ItemSource yet. FetchFailed and LoadMoreFailed are shown as
+	// status messages, given the error, when items can't be fetched from
+	// the ItemSource or loaded by LoadMore.
+	Loading        string
+	FetchFailed    string
+	LoadMoreFailed string
+}
+
+// DefaultStatusStrings returns the default text of the status bar, status
+// messages and placeholders.
+func DefaultStatusStrings() StatusStrings {
+	return StatusStrings{
+		NoItems:         "No %s",
+		NoItemsFound:    "No items found.",
+		NothingMatched:  "Nothing matched",
+		Filtered:        "%d filtered",
+		SortedBy:        "sorted by %s",
+		Selected:        "%d selected",
+		NoSearchMatches: "no matches",
+		SearchMatch:     "%d match",
+		SearchMatches:   "%d matches",
+		SearchPosition:  "%d/%d",
+		Loading:         "Loading" + ellipsis,
+		FetchFailed:     "Couldn't load items: %v",
+		LoadMoreFailed:  "Couldn't load more items: %v",
+	}
+}
+
+// DefaultStatusSegments returns the segments of the built-in status bar: the
+// number of items or the filter results, followed by how many items are
+// filtered out, the active sort and how many items are selected, where they
+// apply. This is useful for adding to the status bar in StatusSegmentsFunc.
+func (m Model) DefaultStatusSegments() []string {
+	var (
+		segments     []string
+		status       string
+		totalItems   = len(m.items)
+		visibleItems = len(m.VisibleItems())
+	)
 
 	if m.filterState == Filtering {
 		// Filter results
 		if visibleItems == 0 {
-			status = m.Styles.StatusEmpty.Render("Nothing matched")
+			status = m.Styles.StatusEmpty.Render(m.StatusStrings.NothingMatched)
 		} else {
-			status = fmt.Sprintf("%d item%s", visibleItems, plural)
+			status = m.itemCount(visibleItems)
 		}
 	} else if len(m.items) == 0 {
 		// Not filtering: no items.
-		status = m.Styles.StatusEmpty.Render("No items")
+		status = m.Styles.StatusEmpty.Render(fmt.Sprintf(m.StatusStrings.NoItems, m.itemNamePlural))
 	} else {
 		// Normal
 		filtered := m.FilterState() == FilterApplied
@@ -1073,16 +1691,38 @@ func (m Model) statusView() string {
 			status += fmt.Sprintf("“%s” ", f)
 		}
 
-		status += fmt.Sprintf("%d item%s", visibleItems, plural)
+		status += m.itemCount(visibleItems)
 	}
+	segments = append(segments, status)
 
 	numFiltered := totalItems - visibleItems
 	if numFiltered > 0 {
-		status += m.Styles.DividerDot.String()
-		status += m.Styles.StatusBarFilterCount.Render(fmt.Sprintf("%d filtered", numFiltered))
+		segments = append(segments, m.Styles.StatusBarFilterCount.Render(fmt.Sprintf(m.StatusStrings.Filtered, numFiltered)))
+	}
+
+	if m.searchState == SearchApplied || (m.searchState == Searching && m.SearchInput.Value() != "") {
+		segments = append(segments, m.searchStatus())
 	}
 
-	return m.Styles.StatusBar.Render(status)
+	if option, ok := m.ActiveSort(); ok {
+		segments = append(segments, m.Styles.StatusBarActiveSort.Render(fmt.Sprintf(m.StatusStrings.SortedBy, option.Name)))
+	}
+
+	if numSelected := len(m.SelectedItems()); m.multiSelect && numSelected > 0 {
+		segments = append(segments, m.Styles.StatusBarSelectedCount.Render(fmt.Sprintf(m.StatusStrings.Selected, numSelected)))
+	}
+
+	return segments
+}
+
+// itemCount returns the given number of items with the singular or plural
+// item name.
+func (m Model) itemCount(n int) string {
+	name := m.itemNamePlural
+	if n == 1 {
+		name = m.itemNameSingular
+	}
+	return fmt.Sprintf("%d %s", n, name)
 }
 
 func (m Model) paginationView() string {
@@ -1090,11 +1730,14 @@ func (m Model) paginationView() string {
 		return ""
 	}
 
-	s := m.Paginator.View()
-
 	// If the dot pagination is wider than the width of the window
-	// use the arabic paginator.
-	if ansi.PrintableRuneWidth(s) > m.width {
+	// use the arabic paginator. Each dot is at least a cell wide, so with
+	// more pages than cells there's no need to render the dots to tell.
+	var s string
+	if m.Paginator.TotalPages <= m.width {
+		s = m.Paginator.View()
+	}
+	if m.Paginator.TotalPages > m.width || ansi.PrintableRuneWidth(s) > m.width {
 		m.Paginator.Type = paginator.Arabic
 		s = m.Styles.ArabicPagination.Render(m.Paginator.View())
 	}
@@ -1110,40 +1753,56 @@ func (m Model) paginationView() string {
 func (m Model) populatedView() string {
 	items := m.VisibleItems()
 
-	var b strings.Builder
+	var (
+		b         strings.Builder
+		headers   int
+		scrollbar = m.showScrollbar()
+	)
+
+	// Make room for the scrollbar
+	if scrollbar {
+		m.width -= scrollbarWidth
+	}
 
 	// Empty states
 	if len(items) == 0 {
 		if m.filterState == Filtering {
 			return ""
 		}
-		return m.Styles.NoItems.Render("No items found.")
+		return m.Styles.NoItems.Render(m.StatusStrings.NoItemsFound)
 	}
 
 	if len(items) > 0 {
-		start, end := m.Paginator.GetSliceBounds(len(items))
+		start, end := m.pageBounds(m.Paginator.Page)
 		docs := items[start:end]
 
-		for i, item := range docs {
-			m.delegate.Render(&b, m, i+start, item)
-			if i != len(docs)-1 {
-				fmt.Fprint(&b, strings.Repeat("\n", m.delegate.Spacing()+1))
+		if hasGroups(docs) {
+			headers = m.renderGroupedPage(&b, docs, start)
+		} else {
+			for i, item := range docs {
+				m.renderItem(&b, i+start, item)
+				if i != len(docs)-1 {
+					fmt.Fprint(&b, strings.Repeat("\n", m.delegate.Spacing()+1))
+				}
 			}
 		}
 	}
 
-	// If there aren't enough items to fill up this page (always the last page)
-	// then we need to add some newlines to fill up the space where items would
-	// have been.
-	itemsOnPage := m.Paginator.ItemsOnPage(len(items))
+	// If there aren't enough items to fill up this page then we need to add
+	// some newlines to fill up the space where items would have been. Group
+	// headers take up some of that space.
+	itemsOnPage := m.itemsOnPage()
 	if itemsOnPage < m.Paginator.PerPage {
-		n := (m.Paginator.PerPage - itemsOnPage) * (m.delegate.Height() + m.delegate.Spacing())
+		n := (m.Paginator.PerPage-itemsOnPage)*(m.delegate.Height()+m.delegate.Spacing()) - headers*headerHeight
 		if len(items) == 0 {
 			n -= m.delegate.Height() - 1
 		}
 		fmt.Fprint(&b, strings.Repeat("\n", n))
 	}
 
+	if scrollbar {
+		return m.withScrollbar(b.String())
+	}
 	return b.String()
 }
 
@@ -1155,31 +1814,6 @@ func (m Model) spinnerView() string {
 	return m.spinner.View()
 }
 
-func filterItems(m Model) tea.Cmd {
-	return func() tea.Msg {
-		if m.FilterInput.Value() == "" || m.filterState == Unfiltered {
-			return FilterMatchesMsg(m.itemsAsFilterItems()) // return nothing
-		}
-
-		targets := []string{}
-		items := m.items
-
-		for _, t := range items {
-			targets = append(targets, t.FilterValue())
-		}
-
-		filterMatches := []filteredItem{}
-		for _, r := range m.Filter(m.FilterInput.Value(), targets) {
-			filterMatches = append(filterMatches, filteredItem{
-				item:    items[r.Index],
-				matches: r.MatchedIndexes,
-			})
-		}
-
-		return FilterMatchesMsg(filterMatches)
-	}
-}
-
 func insertItemIntoSlice(items []Item, item Item, index int) []Item {
 	if items == nil {
 		return []Item{item}
@@ -1206,13 +1840,41 @@ func removeItemFromSlice(i []Item, index int) []Item {
 	return i[:len(i)-1]
 }
 
-func removeFilterMatchFromSlice(i []filteredItem, index int) []filteredItem {
-	if index >= len(i) {
-		return i // noop
+// Remove the match for the item at the given index from a slice of filter
+// matches, shifting the indexes of the items after it. This returns a new
+// slice, since the matches may be shared between the ranked and filtered
+// items.
+func removeFilterMatch(i filteredItems, index int) filteredItems {
+	if i == nil {
+		return nil
 	}
-	copy(i[index:], i[index+1:])
-	i[len(i)-1] = filteredItem{}
-	return i[:len(i)-1]
+	result := make(filteredItems, 0, len(i))
+	for _, fi := range i {
+		if fi.index == index {
+			continue
+		}
+		if fi.index > index {
+			fi.index--
+		}
+		result = append(result, fi)
+	}
+	return result
+}
+
+// Shift the indexes of the matches for items at or after the given index by
+// delta. This returns a new slice, as removeFilterMatch does.
+func shiftFilterMatches(i filteredItems, index, delta int) filteredItems {
+	if i == nil {
+		return nil
+	}
+	result := make(filteredItems, len(i))
+	for j, fi := range i {
+		if fi.index >= index {
+			fi.index += delta
+		}
+		result[j] = fi
+	}
+	return result
 }
 
 func countEnabledBindings(groups [][]key.Binding) (agg int) {
@@ -1226,6 +1888,13 @@ func countEnabledBindings(groups [][]key.Binding) (agg int) {
 	return agg
 }
 
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
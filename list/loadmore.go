@@ -0,0 +1,132 @@
+package list
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// LoadMoreMsg is sent when the cursor reaches the last page of a list that
+// has more items to load and no LoadMore function. Load the items, then add
+// them with AppendItems and call SetHasMore to finish loading.
+type LoadMoreMsg struct{}
+
+// MoreItemsMsg contains items loaded by a list's LoadMore function, which
+// are appended to the list.
+type MoreItemsMsg struct {
+	Items []Item
+
+	// HasMore is whether there are still more items to load after these.
+	HasMore bool
+
+	// Err, if set, is shown as a status message. Loading isn't tried again
+	// until the user moves down on the last page or SetHasMore is called.
+	Err error
+}
+
+// moreItemsMsg is a MoreItemsMsg tagged with the ID of the list that asked
+// for it.
+type moreItemsMsg struct {
+	id  int
+	msg MoreItemsMsg
+}
+
+// SetHasMore sets whether the list has more items to load once the cursor
+// reaches its last page, which makes it scroll infinitely through paginated
+// data. This also finishes any loading that's underway and clears the error
+// from the last attempt, if any.
+func (m *Model) SetHasMore(v bool) {
+	m.hasMore = v
+	m.loadMoreErr = nil
+	if m.loadingMore {
+		m.loadingMore = false
+		m.StopSpinner()
+	}
+}
+
+// HasMore returns whether the list has more items to load.
+func (m Model) HasMore() bool {
+	return m.hasMore
+}
+
+// LoadingMore returns whether the list is waiting for more items to load.
+func (m Model) LoadingMore() bool {
+	return m.loadingMore
+}
+
+// LoadMoreError returns the error loading more items last failed with, if
+// loading hasn't been tried again since.
+func (m Model) LoadMoreError() error {
+	return m.loadMoreErr
+}
+
+// AppendItems adds items to the end of the list. This returns a command.
+func (m *Model) AppendItems(items ...Item) tea.Cmd {
+	var cmd tea.Cmd
+	m.items = append(m.items, items...)
+	m.invalidateFilterTargets()
+	m.sortItems()
+
+	if m.filterState != Unfiltered {
+		cmd = m.filterItems(false)
+	}
+
+	m.updatePagination()
+	m.updateKeybindings()
+	return cmd
+}
+
+// loadMore returns a command that loads more items if the cursor is on the
+// last page and there are more items to load. The spinner is shown while
+// they load.
+func (m *Model) loadMore() tea.Cmd {
+	if !m.hasMore || m.loadingMore || m.loadMoreErr != nil || m.source != nil || m.filterState != Unfiltered {
+		return nil
+	}
+	if _, end := m.pageBounds(m.Paginator.Page); end < len(m.VisibleItems()) {
+		return nil
+	}
+
+	m.loadingMore = true
+	if m.LoadMore == nil {
+		return tea.Batch(m.StartSpinner(), func() tea.Msg {
+			return LoadMoreMsg{}
+		})
+	}
+
+	id := m.id
+	load := m.LoadMore()
+	return tea.Batch(m.StartSpinner(), func() tea.Msg {
+		var msg tea.Msg
+		if load != nil {
+			msg = load()
+		}
+		if more, ok := msg.(MoreItemsMsg); ok {
+			return moreItemsMsg{id: id, msg: more}
+		}
+		return msg
+	})
+}
+
+// retryLoadMore allows loading more items again after an error. It's called
+// when the user moves down, so that loading is only retried when asked for.
+func (m *Model) retryLoadMore() {
+	m.loadMoreErr = nil
+}
+
+// handleMoreItems appends items loaded by LoadMore.
+func (m *Model) handleMoreItems(msg moreItemsMsg) tea.Cmd {
+	if msg.id != m.id {
+		return nil
+	}
+
+	if msg.msg.Err != nil {
+		m.SetHasMore(m.hasMore)
+		m.loadMoreErr = msg.msg.Err
+		return m.NewStatusMessage(fmt.Sprintf(m.StatusStrings.LoadMoreFailed, msg.msg.Err))
+	}
+
+	cmd := m.AppendItems(msg.msg.Items...)
+	m.SetHasMore(msg.msg.HasMore)
+	return tea.Batch(cmd, m.loadMore())
+}
@@ -0,0 +1,170 @@
+package list
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/paginator"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/ansi"
+)
+
+const (
+	defaultMouseWheelDelta = 3
+	doubleClickInterval    = 500 * time.Millisecond
+)
+
+// ItemActivatedMsg is sent when an item is double-clicked.
+type ItemActivatedMsg struct {
+	Index int
+	Item  Item
+}
+
+// handleMouse handles mouse events while browsing. Clicking an item selects
+// it and double-clicking it activates it, clicking a pagination dot goes to
+// that page, and the wheel pages through the list, or scrolls it in
+// scrolling mode. Items can be dragged to move them if MouseDragEnabled is
+// set.
+//
+// Events are expected to be relative to the top left corner of the list.
+func (m *Model) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.MouseWheelUp, tea.MouseWheelDown:
+		if !m.MouseWheelEnabled {
+			return nil
+		}
+		up := msg.Type == tea.MouseWheelUp
+		switch {
+		case m.scrolling && up:
+			m.scrollTo(m.VisibleIndex() - m.MouseWheelDelta)
+		case m.scrolling:
+			m.retryLoadMore()
+			m.scrollTo(m.VisibleIndex() + m.MouseWheelDelta)
+		case up:
+			m.Paginator.PrevPage()
+		default:
+			m.retryLoadMore()
+			m.Paginator.NextPage()
+		}
+
+	case tea.MouseLeft:
+		if !m.MouseClickEnabled {
+			return nil
+		}
+
+		// The terminal reports moving the mouse with the button held as
+		// further presses.
+		if m.mouseDown && m.dragging {
+			m.drag(msg.Y)
+			return nil
+		}
+		wasDown := m.mouseDown
+		m.mouseDown = true
+
+		if page, ok := m.pageAt(msg.X, msg.Y); ok {
+			m.Paginator.Page = page
+			return nil
+		}
+		index, ok := m.itemAt(msg.Y)
+		if !ok || (wasDown && index == m.lastClickIndex) {
+			return nil
+		}
+		m.Select(index)
+		m.startDrag(index)
+
+		now := time.Now()
+		double := index == m.lastClickIndex && now.Sub(m.lastClick) < doubleClickInterval
+		m.lastClick, m.lastClickIndex = now, index
+		if double {
+			m.lastClick = time.Time{}
+			item := m.SelectedItem()
+			return func() tea.Msg {
+				return ItemActivatedMsg{Index: index, Item: item}
+			}
+		}
+
+	case tea.MouseRelease:
+		m.mouseDown = false
+		return m.drop()
+	}
+	return nil
+}
+
+// sectionHeights returns the heights of the title bar and status bar and of
+// the items, as laid out in View.
+func (m Model) sectionHeights() (title, status, items int) {
+	availHeight := m.height
+	if m.showTitle || (m.showFilter && m.filteringEnabled) {
+		title = lipgloss.Height(m.titleView())
+		availHeight -= title
+	}
+	if m.showStatusBar {
+		status = lipgloss.Height(m.statusView())
+		availHeight -= status
+	}
+	if m.showPagination {
+		availHeight -= lipgloss.Height(m.paginationView())
+	}
+	if m.showHelp {
+		availHeight -= lipgloss.Height(m.helpView())
+	}
+	return title, status, max(availHeight, lipgloss.Height(m.populatedView()))
+}
+
+// itemAt returns the index of the visible item at the given line of the list,
+// if any.
+func (m Model) itemAt(y int) (int, bool) {
+	title, status, _ := m.sectionHeights()
+	y -= title + status
+	if y < 0 {
+		return 0, false
+	}
+
+	items := m.VisibleItems()
+	start, end := m.pageBounds(m.Paginator.Page)
+	grouped := hasGroups(items[start:end])
+	line := 0
+	for i := start; i < end; i++ {
+		if group := itemGroup(items[i]); grouped && group != "" && (i == start || group != itemGroup(items[i-1])) {
+			line += headerHeight
+		}
+		if y < line {
+			return 0, false
+		}
+		if y < line+m.delegate.Height() {
+			return i, true
+		}
+		line += m.delegate.Height() + m.delegate.Spacing()
+	}
+	return 0, false
+}
+
+// pageAt returns the page whose pagination dot is at the given position, if
+// any.
+func (m Model) pageAt(x, y int) (int, bool) {
+	if !m.showPagination || m.scrolling || m.Paginator.Type != paginator.Dots || m.Paginator.TotalPages < 2 {
+		return 0, false
+	}
+
+	title, status, items := m.sectionHeights()
+	style := m.Styles.PaginationStyle
+	top := title + status + items + style.GetMarginTop()
+	if m.delegate.Spacing() == 0 && style.GetMarginTop() == 0 {
+		top++
+	}
+	if y != top+style.GetPaddingTop() {
+		return 0, false
+	}
+
+	// Dots are only shown when they fit, otherwise the pages are numbered.
+	dotWidth := ansi.PrintableRuneWidth(m.Paginator.InactiveDot)
+	if dotWidth < 1 || m.Paginator.TotalPages*dotWidth > m.width {
+		return 0, false
+	}
+
+	x -= style.GetMarginLeft() + style.GetPaddingLeft()
+	if x < 0 || x >= m.Paginator.TotalPages*dotWidth {
+		return 0, false
+	}
+	return x / dotWidth, true
+}
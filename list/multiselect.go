@@ -0,0 +1,172 @@
+package list
+
+// KeyedItem is an item that identifies itself in multi-select mode. A
+// KeyedItem stays selected when it moves, and when an item with the same key
+// is passed to SetItems. Items that aren't KeyedItems are identified by their
+// index, so their selection follows them as items are inserted, removed and
+// moved. When the items are replaced with SetItems, their selection carries
+// over to the new items with the same filter values.
+type KeyedItem interface {
+	Item
+
+	// SelectionKey returns the key that identifies the item. It has to be
+	// comparable, such as a string or a number, and unique within the list.
+	SelectionKey() interface{}
+}
+
+// indexKey is the selection key of an item that isn't a KeyedItem.
+type indexKey int
+
+// selectionKey returns the key under which the multi-selection state of the
+// item at the given index is kept.
+func (m Model) selectionKey(index int) interface{} {
+	if item, ok := m.items[index].(KeyedItem); ok {
+		return item.SelectionKey()
+	}
+	return indexKey(index)
+}
+
+// SetMultiSelect enables or disables multi-select mode, in which any number of
+// items can be selected for batch operations. Disabling it clears the
+// selection.
+func (m *Model) SetMultiSelect(v bool) {
+	m.multiSelect = v
+	if !v {
+		m.selected = nil
+	}
+	m.updateKeybindings()
+}
+
+// MultiSelect returns whether or not multi-select mode is enabled.
+func (m Model) MultiSelect() bool {
+	return m.multiSelect
+}
+
+// IsSelected returns whether the item at the given index in Items is
+// selected in multi-select mode. Delegates can get the index of the item
+// they're rendering with ItemIndex.
+func (m Model) IsSelected(index int) bool {
+	if index < 0 || index >= len(m.items) {
+		return false
+	}
+	_, ok := m.selected[m.selectionKey(index)]
+	return ok
+}
+
+// SetSelected selects or deselects the item at the given index in Items in
+// multi-select mode.
+func (m *Model) SetSelected(index int, selected bool) {
+	if !m.multiSelect || index < 0 || index >= len(m.items) || m.items[index] == nil {
+		return
+	}
+	if !selected {
+		delete(m.selected, m.selectionKey(index))
+		return
+	}
+	if m.selected == nil {
+		m.selected = make(map[interface{}]struct{})
+	}
+	m.selected[m.selectionKey(index)] = struct{}{}
+}
+
+// SelectedItems returns the items selected in multi-select mode, in the order
+// in which they appear in the list. Items hidden by the filter are included.
+func (m Model) SelectedItems() []Item {
+	var items []Item
+	for i, item := range m.items {
+		if m.IsSelected(i) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ToggleSelection selects the item under the cursor if it isn't selected,
+// and deselects it otherwise.
+func (m *Model) ToggleSelection() {
+	if m.SelectedItem() == nil {
+		return
+	}
+	i := m.Index()
+	m.SetSelected(i, !m.IsSelected(i))
+}
+
+// SelectAll selects all visible items. When a filter is applied only the
+// items matching it are selected.
+func (m *Model) SelectAll() {
+	for i := range m.VisibleItems() {
+		m.SetSelected(m.ItemIndex(i), true)
+	}
+}
+
+// SelectNone deselects all items, including those hidden by the filter.
+func (m *Model) SelectNone() {
+	m.selected = nil
+}
+
+// InvertSelection toggles the selection of all visible items. When a filter
+// is applied only the items matching it are affected.
+func (m *Model) InvertSelection() {
+	for i := range m.VisibleItems() {
+		index := m.ItemIndex(i)
+		m.SetSelected(index, !m.IsSelected(index))
+	}
+}
+
+// carrySelection carries the selection over from the given items to the ones
+// that have replaced them, and forgets the selection of items that are no
+// longer in the list. KeyedItems are matched by their keys, and other items
+// by their filter values. If several items have the same filter value, as
+// many of them are selected, in order, as were selected before.
+func (m *Model) carrySelection(old []Item) {
+	if len(m.selected) == 0 {
+		return
+	}
+
+	values := make(map[string]int)
+	for i, item := range old {
+		if _, ok := m.selected[indexKey(i)]; ok && item != nil {
+			if _, keyed := item.(KeyedItem); !keyed {
+				values[item.FilterValue()]++
+			}
+		}
+	}
+
+	selected := make(map[interface{}]struct{}, len(m.selected))
+	for i, item := range m.items {
+		switch item := item.(type) {
+		case nil:
+		case KeyedItem:
+			if _, ok := m.selected[item.SelectionKey()]; ok {
+				selected[item.SelectionKey()] = struct{}{}
+			}
+		default:
+			if v := item.FilterValue(); values[v] > 0 {
+				values[v]--
+				selected[indexKey(i)] = struct{}{}
+			}
+		}
+	}
+	m.selected = selected
+}
+
+// moveSelection updates the selection of items that aren't KeyedItems after
+// they've moved, given the new index of the item at each old index, or -1 if
+// it was removed.
+func (m *Model) moveSelection(newIndex func(int) int) {
+	if len(m.selected) == 0 {
+		return
+	}
+	selected := make(map[interface{}]struct{}, len(m.selected))
+	for key := range m.selected {
+		if i, ok := key.(indexKey); ok {
+			j := newIndex(int(i))
+			if j < 0 {
+				continue
+			}
+			key = indexKey(j)
+		}
+		selected[key] = struct{}{}
+	}
+	m.selected = selected
+}
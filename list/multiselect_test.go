@@ -0,0 +1,94 @@
+package list
+
+import (
+	"reflect"
+	"testing"
+)
+
+type sliceItem []string
+
+func (i sliceItem) FilterValue() string { return i[0] }
+
+type keyedItem struct {
+	id   int
+	name string
+}
+
+func (i keyedItem) FilterValue() string       { return i.name }
+func (i keyedItem) SelectionKey() interface{} { return i.id }
+
+func newMultiSelectList(items ...Item) Model {
+	m := New(items, NewDefaultDelegate(), 40, 40)
+	m.SetMultiSelect(true)
+	return m
+}
+
+func TestSelectEqualItems(t *testing.T) {
+	m := newMultiSelectList(groupedItem{name: "a"}, groupedItem{name: "a"})
+	m.SetSelected(1, true)
+
+	if m.IsSelected(0) {
+		t.Error("selecting the second item selected the first, equal one")
+	}
+	if !m.IsSelected(1) {
+		t.Error("second item isn't selected")
+	}
+}
+
+func TestSelectUncomparableItems(t *testing.T) {
+	m := newMultiSelectList(sliceItem{"a"}, sliceItem{"b"})
+	m.ToggleSelection()
+
+	if got := m.SelectedItems(); len(got) != 1 || got[0].FilterValue() != "a" {
+		t.Errorf("SelectedItems() = %v, want [a]", got)
+	}
+}
+
+func TestSelectionFollowsItems(t *testing.T) {
+	m := newMultiSelectList(groupedItem{name: "a"}, groupedItem{name: "b"}, groupedItem{name: "c"})
+	m.SetSelected(1, true)
+
+	m.InsertItem(0, groupedItem{name: "z"})
+	if !m.IsSelected(2) {
+		t.Fatalf("selection didn't follow b after inserting before it")
+	}
+	m.RemoveItem(0)
+	if !m.IsSelected(1) {
+		t.Fatalf("selection didn't follow b after removing before it")
+	}
+	m.moveItem(1, 2)
+	if got := m.SelectedItems(); len(got) != 1 || got[0].FilterValue() != "b" {
+		t.Errorf("SelectedItems() = %v after moving b, want [b]", got)
+	}
+}
+
+func TestKeyedSelectionSurvivesSetItems(t *testing.T) {
+	m := newMultiSelectList(keyedItem{1, "a"}, keyedItem{2, "b"})
+	m.SetSelected(1, true)
+
+	m.SetItems([]Item{keyedItem{2, "b"}, keyedItem{3, "c"}})
+	if !m.IsSelected(0) || m.IsSelected(1) {
+		t.Errorf("selection of keyed item b was lost")
+	}
+}
+
+func TestSelectionSurvivesSetItems(t *testing.T) {
+	m := newMultiSelectList(
+		groupedItem{name: "a"}, groupedItem{name: "b"}, groupedItem{name: "c"},
+	)
+	m.SetSelected(0, true)
+	m.SetSelected(2, true)
+
+	// The items are refreshed, in a different order and without b.
+	m.SetItems([]Item{
+		groupedItem{name: "c"}, groupedItem{name: "d"}, groupedItem{name: "a"},
+	})
+
+	var got []string
+	for _, item := range m.SelectedItems() {
+		got = append(got, item.FilterValue())
+	}
+	if want := []string{"c", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectedItems() = %v after SetItems, want %v", got, want)
+	}
+}
@@ -0,0 +1,253 @@
+package list
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const defaultPreviewRatio = 0.5
+
+// PreviewFunc returns the content to show in the preview pane for an item,
+// given the size of the pane. To load the content asynchronously, return
+// placeholder content along with a command that sends a PreviewMsg once it's
+// loaded.
+type PreviewFunc func(item Item, width, height int) (string, tea.Cmd)
+
+// PreviewMsg contains preview content loaded by a command returned from a
+// list's PreviewFunc. It's dropped if the preview has since been loaded for
+// another item.
+type PreviewMsg struct {
+	Item    Item
+	Content string
+}
+
+// previewMsg is a PreviewMsg tagged with the ID of the list that asked for
+// it, and the generation of the preview it was loaded for.
+type previewMsg struct {
+	id  int
+	gen int
+	msg PreviewMsg
+}
+
+// SetShowPreview shows or hides the preview pane, which is laid out to the
+// right of the list and shows content for the selected item, like fzf's
+// --preview. The content comes from PreviewFunc. This returns a command,
+// which loads the content if needed.
+func (m *Model) SetShowPreview(v bool) tea.Cmd {
+	m.showPreview = v
+	m.previewIndex = -1
+	if !v {
+		m.previewFocused = false
+	}
+	m.layout()
+	m.updateKeybindings()
+	return m.updatePreview()
+}
+
+// ShowPreview returns whether the preview pane is shown.
+func (m Model) ShowPreview() bool {
+	return m.showPreview
+}
+
+// SetPreviewRatio sets the share of the width taken by the preview pane, from
+// 0 to 1. By default, the list and preview pane share the width equally.
+func (m *Model) SetPreviewRatio(ratio float64) {
+	m.previewRatio = ratio
+	m.layout()
+}
+
+// PreviewRatio returns the share of the width taken by the preview pane.
+func (m Model) PreviewRatio() float64 {
+	return m.previewRatio
+}
+
+// FocusPreview focuses the preview pane, so that keys scroll its content
+// rather than move the cursor.
+func (m *Model) FocusPreview() {
+	if !m.showPreview {
+		return
+	}
+	m.previewFocused = true
+	m.updateKeybindings()
+}
+
+// FocusList focuses the list, taking focus away from the preview pane.
+func (m *Model) FocusList() {
+	m.previewFocused = false
+	m.updateKeybindings()
+}
+
+// PreviewFocused returns whether the preview pane has focus.
+func (m Model) PreviewFocused() bool {
+	return m.previewFocused
+}
+
+// RefreshPreview reloads the content of the preview pane. This returns a
+// command, which loads the content if needed.
+func (m *Model) RefreshPreview() tea.Cmd {
+	m.previewIndex = -1
+	return m.updatePreview()
+}
+
+// updatePreview returns a command that loads the preview for the selected
+// item if it isn't already shown. The previewed item is tracked by its index
+// in the items, and every preview that's loaded starts a new generation, so
+// that content loaded for an earlier one is dropped.
+func (m *Model) updatePreview() tea.Cmd {
+	if !m.showPreview || m.PreviewFunc == nil {
+		return nil
+	}
+
+	item := m.SelectedItem()
+	if item == nil {
+		m.previewIndex = -1
+		m.Preview.SetContent("")
+		return nil
+	}
+	index := m.Index()
+	if index == m.previewIndex {
+		return nil
+	}
+
+	m.previewIndex = index
+	m.previewGen++
+	content, load := m.PreviewFunc(item, m.Preview.Width, m.Preview.Height)
+	m.Preview.SetContent(content)
+	m.Preview.GotoTop()
+	if load == nil {
+		return nil
+	}
+
+	id, gen := m.id, m.previewGen
+	return func() tea.Msg {
+		msg := load()
+		if preview, ok := msg.(PreviewMsg); ok {
+			return previewMsg{id: id, gen: gen, msg: preview}
+		}
+		return msg
+	}
+}
+
+// movePreview keeps track of the previewed item after the items have moved,
+// given the new index of the item at each old index, or -1 if it was removed.
+func (m *Model) movePreview(newIndex func(int) int) {
+	if m.previewIndex >= 0 {
+		m.previewIndex = newIndex(m.previewIndex)
+	}
+}
+
+// handlePreview shows loaded preview content, if it's for the latest preview.
+func (m *Model) handlePreview(msg previewMsg) {
+	if msg.id != m.id || msg.gen != m.previewGen || m.previewIndex < 0 {
+		return
+	}
+	m.Preview.SetContent(msg.msg.Content)
+	m.Preview.GotoTop()
+}
+
+// Updates for when the preview pane has focus.
+func (m *Model) handlePreviewFocused(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.KeyMap.SwitchFocus):
+			m.FocusList()
+			return nil
+
+		case key.Matches(msg, m.KeyMap.Quit):
+			return tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.Preview, cmd = m.Preview.Update(msg)
+	return cmd
+}
+
+// previewHelp returns the bindings to show in the help while the preview pane
+// has focus.
+func (m Model) previewHelp() []key.Binding {
+	return []key.Binding{
+		m.Preview.KeyMap.Up,
+		m.Preview.KeyMap.Down,
+		m.Preview.KeyMap.PageUp,
+		m.Preview.KeyMap.PageDown,
+		m.KeyMap.SwitchFocus,
+		m.KeyMap.Quit,
+	}
+}
+
+// inPreview returns whether the given mouse event is over the preview pane.
+func (m Model) inPreview(msg tea.MouseMsg) bool {
+	return m.showPreview && msg.X >= m.width
+}
+
+// handlePreviewMouse handles mouse events over the preview pane. The wheel
+// scrolls it, and clicking it focuses it.
+func (m *Model) handlePreviewMouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft && m.MouseClickEnabled {
+		m.FocusPreview()
+	}
+	if !m.MouseWheelEnabled {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.Preview, cmd = m.Preview.Update(msg)
+	return cmd
+}
+
+// previewStyle returns the style of the preview pane, depending on whether
+// it has focus.
+func (m Model) previewStyle() lipgloss.Style {
+	if m.previewFocused {
+		return m.Styles.PreviewFocused
+	}
+	return m.Styles.Preview
+}
+
+// previewView renders the preview pane.
+func (m Model) previewView() string {
+	content := lipgloss.NewStyle().
+		MaxWidth(m.Preview.Width).
+		MaxHeight(m.Preview.Height).
+		Render(m.Preview.View())
+	content = lipgloss.Place(m.Preview.Width, m.Preview.Height, lipgloss.Left, lipgloss.Top, content)
+	return m.previewStyle().Render(content)
+}
+
+// layout splits the width between the list and the preview pane, if it's
+// shown, and sizes the list's parts to fit.
+func (m *Model) layout() {
+	width := m.fullWidth
+	if m.showPreview {
+		previewWidth := int(float64(width) * clampRatio(m.previewRatio))
+		width -= previewWidth
+
+		// Measure the frame by rendering it, since borders count towards the
+		// frame size even on sides where they're turned off.
+		frame := m.previewStyle().Render("")
+		m.Preview.Width = max(0, previewWidth-lipgloss.Width(frame))
+		m.Preview.Height = max(0, m.height-lipgloss.Height(frame)+1)
+	}
+
+	promptWidth := lipgloss.Width(m.Styles.Title.Render(m.FilterInput.Prompt))
+
+	m.width = width
+	m.Help.Width = width
+	m.FilterInput.Width = width - promptWidth - lipgloss.Width(m.spinnerView())
+
+	promptWidth = lipgloss.Width(m.Styles.Title.Render(m.SearchInput.Prompt))
+	m.SearchInput.Width = width - promptWidth - lipgloss.Width(m.spinnerView())
+	m.updatePagination()
+}
+
+// clampRatio restricts a ratio to the range [0, 1].
+func clampRatio(r float64) float64 {
+	if r < 0 {
+		return 0
+	}
+	if r > 1 {
+		return 1
+	}
+	return r
+}
@@ -0,0 +1,62 @@
+package list
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// funcItem can't be compared by value, since funcs are only equal to nil.
+type funcItem struct {
+	name string
+	fn   func()
+}
+
+func (i funcItem) FilterValue() string { return i.name }
+
+func TestPreviewLoadedOnce(t *testing.T) {
+	m := New([]Item{funcItem{"a", func() {}}, funcItem{"b", func() {}}}, NewDefaultDelegate(), 80, 20)
+	calls := 0
+	m.PreviewFunc = func(item Item, width, height int) (string, tea.Cmd) {
+		calls++
+		return item.FilterValue(), nil
+	}
+	m.SetShowPreview(true)
+
+	for i := 0; i < 3; i++ {
+		m, _ = m.Update(statusMessageTimeoutMsg{})
+	}
+	if calls != 1 {
+		t.Errorf("PreviewFunc called %d times without the cursor moving, want 1", calls)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if calls != 2 {
+		t.Errorf("PreviewFunc called %d times after moving the cursor, want 2", calls)
+	}
+}
+
+func TestStalePreviewDropped(t *testing.T) {
+	// Both items have the same value, so only the generation tells their
+	// previews apart.
+	m := New([]Item{groupedItem{name: "a"}, groupedItem{name: "a"}}, NewDefaultDelegate(), 80, 20)
+	m.PreviewFunc = func(item Item, width, height int) (string, tea.Cmd) {
+		return "loading", func() tea.Msg {
+			return PreviewMsg{Item: item, Content: "loaded"}
+		}
+	}
+	first := m.SetShowPreview(true)
+
+	m.CursorDown()
+	second := m.updatePreview()
+
+	m, _ = m.Update(first())
+	if got := strings.TrimSpace(m.Preview.View()); got != "loading" {
+		t.Errorf("preview for the first item was shown for the second: %q", got)
+	}
+	m, _ = m.Update(second())
+	if got := strings.TrimSpace(m.Preview.View()); got != "loaded" {
+		t.Errorf("preview = %q, want loaded", got)
+	}
+}
@@ -0,0 +1,131 @@
+package list
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// ReorderedMsg is sent when the user moves an item to a new position, so the
+// new order can be persisted. From and To are indexes into the list's items.
+// Dragging an item sends a single message when it's dropped.
+type ReorderedMsg struct {
+	From int
+	To   int
+	Item Item
+}
+
+// SetReorderable sets whether the user can move items with the keyboard, and
+// with the mouse if MouseDragEnabled is set. Reordering is unavailable while
+// the items are filtered, sorted or grouped, or backed by an ItemSource, since
+// the order they're shown in then isn't theirs.
+func (m *Model) SetReorderable(v bool) {
+	m.reorderable = v
+	m.updateKeybindings()
+}
+
+// Reorderable returns whether the user can move items.
+func (m Model) Reorderable() bool {
+	return m.reorderable
+}
+
+// canReorder returns whether the user can move items right now.
+func (m Model) canReorder() bool {
+	return m.reorderable && m.filterState == Unfiltered && m.orderedItems == nil && m.source == nil
+}
+
+// MoveItem moves the item at index from to index to, shifting the items in
+// between, and keeps the cursor on the moved item. This returns a command,
+// which sends a ReorderedMsg, and filters the items again if the list is
+// filtered. Items backed by an ItemSource can't be moved.
+func (m *Model) MoveItem(from, to int) tea.Cmd {
+	if m.source != nil || from < 0 || from >= len(m.items) || to < 0 || to >= len(m.items) || from == to {
+		return nil
+	}
+
+	item := m.moveItem(from, to)
+
+	// Batches from a filter that's still running were matched against the
+	// items before the move, so it's started over.
+	return tea.Batch(m.refilter(), func() tea.Msg {
+		return ReorderedMsg{From: from, To: to, Item: item}
+	})
+}
+
+// moveItem moves an item without sending a ReorderedMsg and returns it.
+func (m *Model) moveItem(from, to int) Item {
+	item := m.items[from]
+	if from < to {
+		copy(m.items[from:to], m.items[from+1:to+1])
+	} else {
+		copy(m.items[to+1:from+1], m.items[to:from])
+	}
+	m.items[to] = item
+
+	newIndex := func(i int) int {
+		switch {
+		case i == from:
+			return to
+		case from < to && i > from && i <= to:
+			return i - 1
+		case to < from && i >= to && i < from:
+			return i + 1
+		}
+		return i
+	}
+	m.moveSelection(newIndex)
+	m.movePreview(newIndex)
+	for i := range m.rankedItems {
+		m.rankedItems[i].index = newIndex(m.rankedItems[i].index)
+	}
+
+	m.invalidateFilterTargets()
+	m.sortItems()
+	if m.filterState != Unfiltered {
+		m.setFilteredItems(m.rankedItems)
+	}
+	m.updatePagination()
+
+	m.selectItemIndex(to)
+	return item
+}
+
+// moveSelectedItem moves the item under the cursor by the given offset.
+func (m *Model) moveSelectedItem(offset int) tea.Cmd {
+	index := m.Index()
+	return m.MoveItem(index, max(0, min(index+offset, len(m.items)-1)))
+}
+
+// startDrag starts dragging the item at the given index, if items can be
+// dragged.
+func (m *Model) startDrag(index int) {
+	if !m.MouseDragEnabled || !m.canReorder() {
+		return
+	}
+	m.dragging = true
+	m.dragFrom = index
+	m.dragIndex = index
+}
+
+// drag moves the dragged item to the item at the given line.
+func (m *Model) drag(y int) {
+	if !m.dragging {
+		return
+	}
+	if index, ok := m.itemAt(y); ok && index != m.dragIndex {
+		m.moveItem(m.dragIndex, index)
+		m.dragIndex = index
+	}
+}
+
+// drop finishes dragging. This returns a command, which sends a ReorderedMsg
+// if the item was moved.
+func (m *Model) drop() tea.Cmd {
+	if !m.dragging {
+		return nil
+	}
+	m.dragging = false
+	if m.dragFrom == m.dragIndex {
+		return nil
+	}
+	msg := ReorderedMsg{From: m.dragFrom, To: m.dragIndex, Item: m.items[m.dragIndex]}
+	return func() tea.Msg {
+		return msg
+	}
+}
@@ -0,0 +1,102 @@
+package list
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	defaultScrollOff = 2
+	scrollbarWidth   = 1
+)
+
+// SetScrolling sets whether the list scrolls continuously rather than paging.
+// In scrolling mode the view follows the cursor, keeping ScrollOff items in
+// view above and below it where possible, and a scrollbar is shown instead of
+// pagination dots.
+func (m *Model) SetScrolling(v bool) {
+	index := m.VisibleIndex()
+	m.scrolling = v
+	m.offset = 0
+	m.Paginator.Page = 0
+	m.cursor = index
+	m.updatePagination()
+	m.updateKeybindings()
+}
+
+// Scrolling returns whether the list scrolls continuously rather than paging.
+func (m Model) Scrolling() bool {
+	return m.scrolling
+}
+
+// scrollTo moves the cursor to the visible item at the given index, scrolling
+// the view as little as possible to keep the scroll-off margin around it.
+func (m *Model) scrollTo(index int) {
+	items := m.VisibleItems()
+	numItems := len(items)
+	index = max(0, min(index, numItems-1))
+	margin := max(0, min(m.ScrollOff, (m.Paginator.PerPage-1)/2))
+
+	// Keep the margin above the cursor.
+	if index-margin < m.offset {
+		m.offset = max(0, index-margin)
+	}
+
+	// Keep the margin below the cursor. No more than a page of items fits in
+	// view, so we can skip ahead to there before checking the height of the
+	// items.
+	bottom := min(numItems, index+margin+1)
+	m.offset = max(m.offset, bottom-m.Paginator.PerPage)
+	for m.offset < index && m.fitItems(items, m.offset) < bottom {
+		m.offset++
+	}
+
+	// Don't leave space empty at the bottom while there are items above.
+	for m.offset > 0 && m.fitItems(items, m.offset-1) >= numItems {
+		m.offset--
+	}
+
+	m.cursor = index - m.offset
+}
+
+// showScrollbar returns whether the scrollbar should be shown, which is when
+// the list is in scrolling mode and not all of the items fit in view.
+func (m Model) showScrollbar() bool {
+	return m.scrolling && m.itemsOnPage() < len(m.VisibleItems())
+}
+
+// scrollbarView renders a scrollbar of the given height.
+func (m Model) scrollbarView(height int) string {
+	numItems := len(m.VisibleItems())
+	start, end := m.pageBounds(m.Paginator.Page)
+	if numItems == 0 || height < 1 {
+		return ""
+	}
+
+	thumbHeight := max(1, height*(end-start)/numItems)
+	thumbTop := min(height*start/numItems, height-thumbHeight)
+	if end >= numItems {
+		thumbTop = height - thumbHeight
+	}
+
+	lines := make([]string, height)
+	for i := range lines {
+		if i >= thumbTop && i < thumbTop+thumbHeight {
+			lines[i] = m.Styles.ScrollbarThumb.String()
+		} else {
+			lines[i] = m.Styles.ScrollbarTrack.String()
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// withScrollbar places the scrollbar to the right of the rendered items. The
+// model's width should already leave room for the scrollbar.
+func (m Model) withScrollbar(items string) string {
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		lipgloss.PlaceHorizontal(m.width, lipgloss.Left, items),
+		m.scrollbarView(lipgloss.Height(items)),
+	)
+}
@@ -0,0 +1,248 @@
+package list
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SearchState describes the current searching state on the model. Unlike
+// filtering, searching keeps every item visible and moves the cursor between
+// the items that match.
+type SearchState int
+
+// Possible search states.
+const (
+	NotSearching  SearchState = iota // no search set
+	Searching                        // user is actively typing a search term
+	SearchApplied                    // a search term is set
+)
+
+// String returns a human-readable string of the current search state.
+func (s SearchState) String() string {
+	return [...]string{
+		"not searching",
+		"searching",
+		"search applied",
+	}[s]
+}
+
+// SearchState returns the current search state.
+func (m Model) SearchState() SearchState {
+	return m.searchState
+}
+
+// SearchValue returns the current value of the search.
+func (m Model) SearchValue() string {
+	return m.SearchInput.Value()
+}
+
+// SearchMatchesForItem returns rune positions matched by the search, if any,
+// for the visible item at the given index.
+func (m Model) SearchMatchesForItem(index int) []int {
+	return m.searchRunes[index]
+}
+
+// SearchMatches returns the indexes of the visible items that match the
+// search, in order.
+func (m Model) SearchMatches() []int {
+	return m.searchMatches
+}
+
+// StartSearch puts the list into the searching state, in which the user can
+// type a search term. This returns a command.
+func (m *Model) StartSearch() tea.Cmd {
+	m.hideStatusMessage()
+	m.searchState = Searching
+	m.searchOrigin = m.VisibleIndex()
+	m.SearchInput.CursorEnd()
+	m.SearchInput.Focus()
+	m.updateKeybindings()
+	return textinput.Blink
+}
+
+// ClearSearch clears the search, if any.
+func (m *Model) ClearSearch() {
+	m.searchState = NotSearching
+	m.SearchInput.Reset()
+	m.SearchInput.Blur()
+	m.searchMatches = nil
+	m.searchRunes = nil
+	m.searchTerm = ""
+	m.updatePagination()
+	m.updateKeybindings()
+}
+
+// NextMatch moves the cursor to the next item matching the search, wrapping
+// around after the last one.
+func (m *Model) NextMatch() {
+	if len(m.searchMatches) == 0 {
+		return
+	}
+	index := m.VisibleIndex()
+	for _, i := range m.searchMatches {
+		if i > index {
+			m.Select(i)
+			return
+		}
+	}
+	m.Select(m.searchMatches[0])
+}
+
+// PrevMatch moves the cursor to the previous item matching the search,
+// wrapping around before the first one.
+func (m *Model) PrevMatch() {
+	if len(m.searchMatches) == 0 {
+		return
+	}
+	index := m.VisibleIndex()
+	for j := len(m.searchMatches) - 1; j >= 0; j-- {
+		if i := m.searchMatches[j]; i < index {
+			m.Select(i)
+			return
+		}
+	}
+	m.Select(m.searchMatches[len(m.searchMatches)-1])
+}
+
+// invalidateSearch marks the search matches as stale. It should be called
+// whenever the visible items change.
+func (m *Model) invalidateSearch() {
+	m.searchStale = true
+}
+
+// updateSearch matches the visible items against the search term, unless
+// neither has changed since they were last matched.
+func (m *Model) updateSearch() {
+	term := m.SearchInput.Value()
+	if m.searchState == NotSearching {
+		term = ""
+	}
+	if term == m.searchTerm && !m.searchStale {
+		return
+	}
+
+	m.searchTerm = term
+	m.searchStale = false
+	m.searchMatches = nil
+	m.searchRunes = nil
+	if term == "" {
+		return
+	}
+
+	items := m.VisibleItems()
+	targets := make([]string, len(items))
+	for i, item := range items {
+		if item != nil {
+			targets[i] = item.FilterValue()
+		}
+	}
+
+	search := m.SearchFunc
+	if search == nil {
+		search = SubstringFilter
+	}
+	ranks := search(term, targets)
+
+	m.searchRunes = make(map[int][]int, len(ranks))
+	for _, r := range ranks {
+		if items[r.Index] == nil {
+			continue
+		}
+		if _, ok := m.searchRunes[r.Index]; !ok {
+			m.searchMatches = append(m.searchMatches, r.Index)
+		}
+		m.searchRunes[r.Index] = r.MatchedIndexes
+	}
+	sort.Ints(m.searchMatches)
+}
+
+// jumpToMatch moves the cursor to the first match at or after the item the
+// cursor was on when the search started, wrapping around after the last one.
+// If nothing matches the cursor goes back to where it was.
+func (m *Model) jumpToMatch() {
+	if len(m.searchMatches) == 0 {
+		m.Select(m.searchOrigin)
+		return
+	}
+	for _, i := range m.searchMatches {
+		if i >= m.searchOrigin {
+			m.Select(i)
+			return
+		}
+	}
+	m.Select(m.searchMatches[0])
+}
+
+// Updates for when a user is typing a search term.
+func (m *Model) handleSearching(msg tea.Msg) tea.Cmd {
+	// Handle keys
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.KeyMap.CancelSearch):
+			m.ClearSearch()
+			m.Select(m.searchOrigin)
+			return nil
+
+		case key.Matches(msg, m.KeyMap.AcceptSearch):
+			if m.SearchInput.Value() == "" {
+				m.ClearSearch()
+				return nil
+			}
+			m.SearchInput.Blur()
+			m.searchState = SearchApplied
+			m.updateKeybindings()
+			return nil
+		}
+	}
+
+	// Update the search text input component
+	newSearchInputModel, cmd := m.SearchInput.Update(msg)
+	searchChanged := m.SearchInput.Value() != newSearchInputModel.Value()
+	m.SearchInput = newSearchInputModel
+
+	// If the search term has changed, jump to the first match
+	if searchChanged {
+		m.updateSearch()
+		m.jumpToMatch()
+	}
+
+	return cmd
+}
+
+// searchStatus returns the status bar segment for the search, showing the
+// number of matches and which of them the cursor is on.
+func (m Model) searchStatus() string {
+	numMatches := len(m.searchMatches)
+	if numMatches == 0 {
+		return m.Styles.StatusEmpty.Render(m.StatusStrings.NoSearchMatches)
+	}
+
+	format := m.StatusStrings.SearchMatches
+	if numMatches == 1 {
+		format = m.StatusStrings.SearchMatch
+	}
+	status := fmt.Sprintf(format, numMatches)
+	for i, index := range m.searchMatches {
+		if index == m.VisibleIndex() {
+			status = fmt.Sprintf(m.StatusStrings.SearchPosition, i+1, numMatches)
+			break
+		}
+	}
+	return m.Styles.StatusBarSearchMatches.Render(status)
+}
+
+// sharesKeys returns whether two keybindings have any keys in common.
+func sharesKeys(a, b key.Binding) bool {
+	for _, k := range a.Keys() {
+		for _, l := range b.Keys() {
+			if k == l {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package list
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var (
+	slashKey  = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}}
+	searchKey = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'\\'}}
+)
+
+func TestSearchKey(t *testing.T) {
+	m := newGroupedList()
+	m, _ = m.Update(searchKey)
+	if m.SearchState() != Searching {
+		t.Fatalf("the default Search key didn't start searching")
+	}
+
+	m = newGroupedList()
+	m, _ = m.Update(slashKey)
+	if m.FilterState() != Filtering || m.SearchState() != NotSearching {
+		t.Fatalf("/ started %v and %v, want filtering", m.FilterState(), m.SearchState())
+	}
+}
+
+func TestSearchKeySharedWithFilter(t *testing.T) {
+	m := newGroupedList()
+	m.KeyMap.Search.SetKeys("/")
+	m.updateKeybindings()
+	m, _ = m.Update(slashKey)
+	if m.FilterState() != Filtering || m.SearchState() != NotSearching {
+		t.Fatalf("/ started %v and %v, want filtering", m.FilterState(), m.SearchState())
+	}
+
+	m = newGroupedList()
+	m.KeyMap.Search.SetKeys("/")
+	m.SetFilteringEnabled(false)
+	m, _ = m.Update(slashKey)
+	if m.SearchState() != Searching {
+		t.Errorf("/ didn't start searching with filtering disabled")
+	}
+}
+
+func TestSearchMatchesCached(t *testing.T) {
+	m := newGroupedList()
+
+	calls := 0
+	m.SearchFunc = func(term string, targets []string) []Rank {
+		calls++
+		return SubstringFilter(term, targets)
+	}
+
+	m, _ = m.Update(searchKey)
+	for _, r := range "an" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if calls != 2 {
+		t.Fatalf("SearchFunc called %d times for 2 keystrokes", calls)
+	}
+	if want := []int{1}; !reflect.DeepEqual(m.SearchMatches(), want) {
+		t.Errorf("SearchMatches() = %v, want %v", m.SearchMatches(), want)
+	}
+
+	// Moving around and resizing doesn't search again.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.CursorDown()
+	m.SetSize(30, 30)
+	if calls != 2 {
+		t.Errorf("SearchFunc called again without the term or items changing")
+	}
+
+	// Changing the items does.
+	m.InsertItem(0, groupedItem{"orange", "fruit"})
+	if calls != 3 {
+		t.Errorf("SearchFunc wasn't called after the items changed")
+	}
+	if want := []int{0, 2}; !reflect.DeepEqual(m.SearchMatches(), want) {
+		t.Errorf("SearchMatches() = %v, want %v", m.SearchMatches(), want)
+	}
+}
@@ -0,0 +1,172 @@
+package list
+
+import (
+	"sort"
+	"strings"
+)
+
+// LessFunc reports whether item a should be shown before item b.
+type LessFunc func(a, b Item) bool
+
+// SortOption is a named order in which the items can be shown.
+type SortOption struct {
+	// Name is shown in the status bar while the list is sorted this way.
+	Name string
+
+	// Less compares two items.
+	Less LessFunc
+}
+
+// SortByFilterValue is a SortOption that sorts items alphabetically by their
+// filter values, ignoring case.
+var SortByFilterValue = SortOption{
+	Name: "name",
+	Less: func(a, b Item) bool {
+		return strings.ToLower(a.FilterValue()) < strings.ToLower(b.FilterValue())
+	},
+}
+
+// SetSortOptions sets the orders in which the user can choose to show the
+// items, in addition to the order of the items themselves. The list goes back
+// to showing the items in their own order.
+func (m *Model) SetSortOptions(options ...SortOption) {
+	m.sortOptions = options
+	m.setSortIndex(0)
+	m.updateKeybindings()
+}
+
+// SortOptions returns the orders in which the user can choose to show the
+// items.
+func (m Model) SortOptions() []SortOption {
+	return m.sortOptions
+}
+
+// Sorted returns whether the list is sorted by one of its sort options rather
+// than shown in the order of its items.
+func (m Model) Sorted() bool {
+	return m.sortIndex > 0 && m.sortIndex <= len(m.sortOptions)
+}
+
+// ActiveSort returns the sort option the list is currently sorted by. The
+// second return value is false if the items are shown in their own order.
+func (m Model) ActiveSort() (SortOption, bool) {
+	if !m.Sorted() {
+		return SortOption{}, false
+	}
+	return m.sortOptions[m.sortIndex-1], true
+}
+
+// SetSort sorts the list by the sort option with the given name. An empty
+// name or one that doesn't belong to any of the options shows the items in
+// their own order. The cursor stays on the same item.
+func (m *Model) SetSort(name string) {
+	index := 0
+	for i, option := range m.sortOptions {
+		if option.Name == name && name != "" {
+			index = i + 1
+			break
+		}
+	}
+	m.setSortIndex(index)
+}
+
+// NextSort sorts the list by the next sort option. After the last option the
+// items are shown in their own order again. The cursor stays on the same
+// item.
+func (m *Model) NextSort() {
+	if len(m.sortOptions) == 0 {
+		return
+	}
+	m.setSortIndex((m.sortIndex + 1) % (len(m.sortOptions) + 1))
+}
+
+// setSortIndex changes the active sort option, keeping the cursor on the same
+// item.
+func (m *Model) setSortIndex(index int) {
+	selected := -1
+	if m.SelectedItem() != nil {
+		selected = m.Index()
+	}
+	m.sortIndex = index
+	m.sortItems()
+	if m.filterState != Unfiltered {
+		m.setFilteredItems(m.rankedItems)
+	}
+	m.updatePagination()
+	m.updateKeybindings()
+
+	if selected >= 0 {
+		m.selectItemIndex(selected)
+	}
+}
+
+// sortItems updates the copy of the items shown when the list isn't filtered,
+// which is sorted by the active sort option and kept together by group. It
+// should be called whenever the items change. Items from an ItemSource are
+// always shown in the source's order.
+func (m *Model) sortItems() {
+	m.invalidateSearch()
+	option, sorted := m.ActiveSort()
+	grouped := hasGroups(m.items)
+	if (!sorted && !grouped) || m.source != nil {
+		m.orderedItems = nil
+		m.orderedIndexes = nil
+		return
+	}
+
+	indexes := make([]int, len(m.items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	if sorted {
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return option.Less(m.items[indexes[i]], m.items[indexes[j]])
+		})
+	}
+	if grouped {
+		groups := make([]string, len(indexes))
+		for i, j := range indexes {
+			groups[i] = itemGroup(m.items[j])
+		}
+		order := groupOrder(groups)
+		grouped := make([]int, len(indexes))
+		for i, j := range order {
+			grouped[i] = indexes[j]
+		}
+		indexes = grouped
+	}
+
+	items := make([]Item, len(indexes))
+	for i, j := range indexes {
+		items[i] = m.items[j]
+	}
+	m.orderedItems = items
+	m.orderedIndexes = indexes
+}
+
+// setFilteredItems sets the items matching the filter, given in order of how
+// well they match, then sorts them if the list is sorted and keeps them
+// together by group. Both are stable, so items that compare equal stay in
+// order of how well they match.
+func (m *Model) setFilteredItems(ranked filteredItems) {
+	m.invalidateSearch()
+	m.rankedItems = ranked
+	option, sorted := m.ActiveSort()
+	grouped := hasGroups(ranked.items())
+	if ranked == nil || (!sorted && !grouped) {
+		m.filteredItems = ranked
+		return
+	}
+
+	items := make(filteredItems, len(ranked))
+	copy(items, ranked)
+	if sorted {
+		sort.SliceStable(items, func(i, j int) bool {
+			return option.Less(items[i].item, items[j].item)
+		})
+	}
+	if grouped {
+		items = groupFilteredItems(items)
+	}
+	m.filteredItems = items
+}
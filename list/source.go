@@ -0,0 +1,164 @@
+package list
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const defaultFetchSize = 100
+
+// ItemSource provides the items in a list on demand, for lists that are too
+// large or too slow to load up front, such as database query results or long
+// logs. Only the items in view are fetched, FetchSize items at a time.
+//
+// Fetch is called in a command, so it can take its time without blocking the
+// program. Items that haven't been fetched yet are shown as placeholders, and
+// SelectedItem returns nil while the cursor is on one.
+//
+// Filtering and sorting need all of the items, so they're disabled while the
+// list is backed by an ItemSource. Multi-select operations like SelectAll
+// only affect items that have been fetched.
+type ItemSource interface {
+	// Len returns the number of items.
+	Len() int
+
+	// Fetch returns the items in the range [start, end).
+	Fetch(start, end int) ([]Item, error)
+}
+
+// itemsFetchedMsg contains items fetched from an ItemSource. It's tagged with
+// the generation of the source, so that items fetched from a source that has
+// since been replaced can be dropped.
+type itemsFetchedMsg struct {
+	id    int
+	gen   int64
+	start int
+	items []Item
+	err   error
+}
+
+// SetItemSource backs the list with the given source, replacing its items.
+// This returns a command, which fetches the items in view.
+func (m *Model) SetItemSource(source ItemSource) tea.Cmd {
+	m.resetFiltering()
+	m.source = source
+	m.sourceGen++
+	m.fetched = make(map[int]bool)
+	m.fetchFailed = nil
+	old := m.items
+	m.items = make([]Item, source.Len())
+	m.previewIndex = -1
+	m.carrySelection(old)
+	m.invalidateFilterTargets()
+	m.sortItems()
+	m.Select(0)
+	m.updatePagination()
+	m.updateKeybindings()
+	return m.fetchItems()
+}
+
+// ItemSource returns the source backing the list, if any.
+func (m Model) ItemSource() ItemSource {
+	return m.source
+}
+
+// RetryFetch fetches the chunks of items that failed to be fetched from the
+// list's ItemSource again. This returns a command. Failed chunks are also
+// fetched again once the list scrolls.
+func (m *Model) RetryFetch() tea.Cmd {
+	for chunk := range m.fetchFailed {
+		delete(m.fetched, chunk)
+	}
+	m.fetchFailed = nil
+	return m.fetchItems()
+}
+
+// fetchItems returns a command that fetches the chunks of items in view that
+// haven't been fetched yet.
+func (m *Model) fetchItems() tea.Cmd {
+	if m.source == nil {
+		return nil
+	}
+
+	start, end := m.pageBounds(m.Paginator.Page)
+	if start != m.fetchStart {
+		m.fetchStart = start
+		return m.RetryFetch()
+	}
+
+	var (
+		cmds []tea.Cmd
+		size = m.fetchSize()
+	)
+	for chunk := start / size; chunk*size < end; chunk++ {
+		if m.fetched[chunk] {
+			continue
+		}
+		m.fetched[chunk] = true
+		cmds = append(cmds, m.fetchChunk(chunk*size, min((chunk+1)*size, len(m.items))))
+	}
+	return tea.Batch(cmds...)
+}
+
+// fetchSize returns the number of items to fetch from the source at once.
+func (m Model) fetchSize() int {
+	if m.FetchSize <= 0 {
+		return defaultFetchSize
+	}
+	return m.FetchSize
+}
+
+// fetchChunk returns a command that fetches the items in the range
+// [start, end) from the source.
+func (m Model) fetchChunk(start, end int) tea.Cmd {
+	var (
+		id     = m.id
+		gen    = m.sourceGen
+		source = m.source
+	)
+	return func() tea.Msg {
+		items, err := source.Fetch(start, end)
+		return itemsFetchedMsg{id: id, gen: gen, start: start, items: items, err: err}
+	}
+}
+
+// handleItemsFetched stores fetched items, unless they're from a source that
+// has since been replaced. A chunk that fails to be fetched is tried again
+// once the list scrolls, or RetryFetch is called.
+func (m *Model) handleItemsFetched(msg itemsFetchedMsg) tea.Cmd {
+	if msg.id != m.id || msg.gen != m.sourceGen {
+		return nil
+	}
+
+	if msg.err != nil {
+		if m.fetchFailed == nil {
+			m.fetchFailed = make(map[int]bool)
+		}
+		m.fetchFailed[msg.start/m.fetchSize()] = true
+		return m.NewStatusMessage(fmt.Sprintf(m.StatusStrings.FetchFailed, msg.err))
+	}
+
+	for i, item := range msg.items {
+		if msg.start+i < len(m.items) {
+			m.items[msg.start+i] = item
+		}
+	}
+	m.invalidateSearch()
+	m.updatePagination()
+	return nil
+}
+
+// renderItem renders the item at the given index with the delegate, or a
+// placeholder if it hasn't been fetched yet.
+func (m Model) renderItem(w io.Writer, index int, item Item) {
+	if item != nil {
+		m.delegate.Render(w, m, index, item)
+		return
+	}
+	lines := make([]string, max(1, m.delegate.Height()))
+	lines[0] = m.Styles.Placeholder.Render(m.StatusStrings.Loading)
+	fmt.Fprint(w, strings.Join(lines, "\n"))
+}
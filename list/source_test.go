@@ -0,0 +1,88 @@
+package list
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// failingSource fails to fetch any items.
+type failingSource struct {
+	n     int
+	calls *int
+}
+
+func (s failingSource) Len() int { return s.n }
+
+func (s failingSource) Fetch(start, end int) ([]Item, error) {
+	*s.calls++
+	return nil, errors.New("offline")
+}
+
+// fetched runs the fetches started by a command, looking into batches, and
+// returns the resulting messages. Other commands aren't run.
+func fetched(cmd tea.Cmd) []itemsFetchedMsg {
+	if cmd == nil {
+		return nil
+	}
+	var msgs []itemsFetchedMsg
+	switch msg := cmd().(type) {
+	case itemsFetchedMsg:
+		msgs = append(msgs, msg)
+	default:
+		// tea.Batch returns an unexported slice of commands.
+		v := reflect.ValueOf(msg)
+		if v.Kind() != reflect.Slice {
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			if c, ok := v.Index(i).Interface().(tea.Cmd); ok {
+				msgs = append(msgs, fetched(c)...)
+			}
+		}
+	}
+	return msgs
+}
+
+// update passes a message to the list, along with the results of the fetches
+// it starts.
+func update(m Model, msg tea.Msg) Model {
+	m, cmd := m.Update(msg)
+	for _, msg := range fetched(cmd) {
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func TestFailedFetchNotRetriedInLoop(t *testing.T) {
+	calls := 0
+	m := New(nil, NewDefaultDelegate(), 40, 10)
+	m.FetchSize = 1000
+	m.StatusMessageLifetime = time.Millisecond
+	for _, msg := range fetched(m.SetItemSource(failingSource{n: 200, calls: &calls})) {
+		m = update(m, msg)
+	}
+	if calls != 1 {
+		t.Fatalf("Fetch called %d times, want 1", calls)
+	}
+
+	for i := 0; i < 5; i++ {
+		m = update(m, statusMessageTimeoutMsg{})
+	}
+	if calls != 1 {
+		t.Errorf("Fetch called %d times without scrolling, want 1", calls)
+	}
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyRight})
+	if calls != 2 {
+		t.Errorf("Fetch called %d times after scrolling, want 2", calls)
+	}
+
+	fetched(m.RetryFetch())
+	if calls != 3 {
+		t.Errorf("Fetch called %d times after RetryFetch, want 3", calls)
+	}
+}
@@ -0,0 +1,45 @@
+package list
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStatusStrings(t *testing.T) {
+	m := newGroupedList()
+	m.StatusStrings.Filtered = "%d masqués"
+	m.StatusStrings.SortedBy = "trié par %s"
+	m.SetSortOptions(SortByFilterValue)
+	m.SetSort(SortByFilterValue.Name)
+	applyFilter(&m, "an")
+
+	status := strings.Join(m.DefaultStatusSegments(), " ")
+	for _, want := range []string{"4 masqués", "trié par " + SortByFilterValue.Name} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status bar %q doesn't contain %q", status, want)
+		}
+	}
+
+	m.SetItems(nil)
+	m.StatusStrings.NoItemsFound = "Rien ici."
+	if view := m.View(); !strings.Contains(view, "Rien ici.") {
+		t.Errorf("empty list doesn't show NoItemsFound:\n%s", view)
+	}
+}
+
+func TestStatusStringsFetchFailed(t *testing.T) {
+	calls := 0
+	m := New(nil, NewDefaultDelegate(), 40, 10)
+	m.StatusStrings.Loading = "Chargement…"
+	m.StatusStrings.FetchFailed = "Échec : %v"
+	m.SetItemSource(failingSource{n: 5, calls: &calls})
+
+	if view := m.View(); !strings.Contains(view, "Chargement…") {
+		t.Errorf("placeholders don't show Loading:\n%s", view)
+	}
+	m.handleItemsFetched(itemsFetchedMsg{id: m.id, gen: m.sourceGen, err: errors.New("offline")})
+	if got := m.StatusMessage(); got != "Échec : offline" {
+		t.Errorf("StatusMessage() = %q, want the FetchFailed text", got)
+	}
+}
@@ -22,12 +22,17 @@ type Styles struct {
 	// overridden by delegates.
 	DefaultFilterCharacterMatch lipgloss.Style
 
-	StatusBar             lipgloss.Style
-	StatusEmpty           lipgloss.Style
-	StatusBarActiveFilter lipgloss.Style
-	StatusBarFilterCount  lipgloss.Style
-
-	NoItems lipgloss.Style
+	StatusBar              lipgloss.Style
+	StatusEmpty            lipgloss.Style
+	StatusBarActiveFilter  lipgloss.Style
+	StatusBarFilterCount   lipgloss.Style
+	StatusBarSelectedCount lipgloss.Style
+	StatusBarActiveSort    lipgloss.Style
+	StatusBarSearchMatches lipgloss.Style
+
+	NoItems     lipgloss.Style
+	GroupHeader lipgloss.Style
+	Placeholder lipgloss.Style
 
 	PaginationStyle lipgloss.Style
 	HelpStyle       lipgloss.Style
@@ -37,6 +42,13 @@ type Styles struct {
 	InactivePaginationDot lipgloss.Style
 	ArabicPagination      lipgloss.Style
 	DividerDot            lipgloss.Style
+	ScrollbarThumb        lipgloss.Style
+	ScrollbarTrack        lipgloss.Style
+
+	// The preview pane, and the preview pane when it has focus. Both should
+	// have frames of the same size.
+	Preview        lipgloss.Style
+	PreviewFocused lipgloss.Style
 }
 
 // DefaultStyles returns a set of default style definitions for this list
@@ -74,9 +86,26 @@ func DefaultStyles() (s Styles) {
 
 	s.StatusBarFilterCount = lipgloss.NewStyle().Foreground(verySubduedColor)
 
+	s.StatusBarActiveSort = lipgloss.NewStyle().Foreground(subduedColor)
+
+	s.StatusBarSearchMatches = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#1a1a1a", Dark: "#dddddd"})
+
+	s.StatusBarSelectedCount = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#EE6FF8", Dark: "#EE6FF8"})
+
 	s.NoItems = lipgloss.NewStyle().
 		Foreground(lipgloss.AdaptiveColor{Light: "#909090", Dark: "#626262"})
 
+	s.GroupHeader = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#847A85", Dark: "#979797"}).
+		Bold(true).
+		Padding(0, 0, 0, 2) //nolint:gomnd
+
+	s.Placeholder = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#909090", Dark: "#626262"}).
+		Padding(0, 0, 0, 2) //nolint:gomnd
+
 	s.ArabicPagination = lipgloss.NewStyle().Foreground(subduedColor)
 
 	s.PaginationStyle = lipgloss.NewStyle().PaddingLeft(2) //nolint:gomnd
@@ -95,5 +124,21 @@ func DefaultStyles() (s Styles) {
 		Foreground(verySubduedColor).
 		SetString(" " + bullet + " ")
 
+	s.ScrollbarThumb = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#847A85", Dark: "#979797"}).
+		SetString("┃")
+
+	s.ScrollbarTrack = lipgloss.NewStyle().
+		Foreground(verySubduedColor).
+		SetString("│")
+
+	s.Preview = lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(verySubduedColor).
+		PaddingLeft(1)
+
+	s.PreviewFocused = s.Preview.Copy().
+		BorderForeground(lipgloss.AdaptiveColor{Light: "#F793FF", Dark: "#AD58B4"})
+
 	return s
 }
@@ -0,0 +1,480 @@
+// Package table provides a Bubble Tea component for displaying and navigating
+// tabular data. Rows are rendered under a styled header, and a highlighted
+// cursor row can be moved through the table, which scrolls like a viewport.
+package table
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	rw "github.com/mattn/go-runewidth"
+)
+
+const ellipsis = "…"
+
+// Row represents one line in the table. Each value corresponds to the column
+// at the same index.
+type Row []string
+
+// Column defines a table column.
+type Column struct {
+	// Title is rendered in the header.
+	Title string
+
+	// Width is the number of cells available to values in this column,
+	// excluding cell padding. Longer values are truncated.
+	Width int
+
+	// Align sets the horizontal alignment of the title and values in this
+	// column.
+	Align lipgloss.Position
+}
+
+// KeyMap defines keybindings. Like the viewport's keybindings, page and
+// half-page motions move by the visible height of the table.
+type KeyMap struct {
+	LineUp       key.Binding
+	LineDown     key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+	GotoTop      key.Binding
+	GotoBottom   key.Binding
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	const spacebar = " "
+	return KeyMap{
+		LineUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		LineDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "b"),
+			key.WithHelp("b/pgup", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", spacebar, "f"),
+			key.WithHelp("f/pgdn", "page down"),
+		),
+		HalfPageUp: key.NewBinding(
+			key.WithKeys("u", "ctrl+u"),
+			key.WithHelp("u", "½ page up"),
+		),
+		HalfPageDown: key.NewBinding(
+			key.WithKeys("d", "ctrl+d"),
+			key.WithHelp("d", "½ page down"),
+		),
+		GotoTop: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "go to start"),
+		),
+		GotoBottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "go to end"),
+		),
+	}
+}
+
+// Styles contains style definitions for this table component. By default,
+// these values are generated by DefaultStyles.
+type Styles struct {
+	Header   lipgloss.Style
+	Cell     lipgloss.Style
+	Selected lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this table
+// component.
+func DefaultStyles() (s Styles) {
+	s.Header = lipgloss.NewStyle().
+		Bold(true).
+		Padding(0, 1).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.AdaptiveColor{Light: "#DDDADA", Dark: "#3C3C3C"}).
+		BorderBottom(true)
+
+	s.Cell = lipgloss.NewStyle().Padding(0, 1)
+
+	s.Selected = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.AdaptiveColor{Light: "#EE6FF8", Dark: "#EE6FF8"})
+
+	return s
+}
+
+// Option is used to set options in New. For example:
+//
+//     table := New(
+//         WithColumns([]Column{{Title: "ID", Width: 10}}),
+//         WithHeight(10),
+//     )
+//
+type Option func(*Model)
+
+// WithColumns sets the table columns (headers).
+func WithColumns(cols []Column) Option {
+	return func(m *Model) {
+		m.cols = cols
+	}
+}
+
+// WithRows sets the table rows (data).
+func WithRows(rows []Row) Option {
+	return func(m *Model) {
+		m.rows = rows
+	}
+}
+
+// WithHeight sets the height of the table, including the header.
+func WithHeight(h int) Option {
+	return func(m *Model) {
+		m.height = h
+	}
+}
+
+// WithWidth sets the width of the table.
+func WithWidth(w int) Option {
+	return func(m *Model) {
+		m.viewport.Width = w
+	}
+}
+
+// WithFocused sets the focus state of the table.
+func WithFocused(f bool) Option {
+	return func(m *Model) {
+		m.focus = f
+	}
+}
+
+// WithStyles sets the table styles.
+func WithStyles(s Styles) Option {
+	return func(m *Model) {
+		m.Styles = s
+	}
+}
+
+// WithKeyMap sets the keymap.
+func WithKeyMap(km KeyMap) Option {
+	return func(m *Model) {
+		m.KeyMap = km
+	}
+}
+
+// Model is the Bubble Tea model for this table element.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	// Whether or not to respond to the mouse. The mouse must be enabled in
+	// Bubble Tea for this to work. For details, see the Bubble Tea docs.
+	MouseWheelEnabled bool
+
+	// The number of rows the mouse wheel will move the cursor. By default,
+	// this is 3.
+	MouseWheelDelta int
+
+	cols   []Column
+	rows   []Row
+	cursor int
+	focus  bool
+	height int
+
+	// viewport handles scrolling of the rows beneath the header.
+	viewport viewport.Model
+}
+
+// New creates a new model for the table widget.
+func New(opts ...Option) Model {
+	m := Model{
+		KeyMap:            DefaultKeyMap(),
+		Styles:            DefaultStyles(),
+		MouseWheelEnabled: true,
+		MouseWheelDelta:   3, //nolint:gomnd
+
+		height:   20, //nolint:gomnd
+		viewport: viewport.New(0, 0),
+	}
+
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	m.updateViewport()
+	return m
+}
+
+// Focused returns the focus state of the table.
+func (m Model) Focused() bool {
+	return m.focus
+}
+
+// Focus focuses the table, allowing the user to move around the rows and
+// interact.
+func (m *Model) Focus() {
+	m.focus = true
+}
+
+// Blur blurs the table, preventing selection or movement.
+func (m *Model) Blur() {
+	m.focus = false
+}
+
+// Rows returns the current rows.
+func (m Model) Rows() []Row {
+	return m.rows
+}
+
+// SetRows sets a new rows state. The cursor is kept in bounds.
+func (m *Model) SetRows(r []Row) {
+	m.rows = r
+	m.updateViewport()
+}
+
+// Columns returns the current columns.
+func (m Model) Columns() []Column {
+	return m.cols
+}
+
+// SetColumns sets a new columns state.
+func (m *Model) SetColumns(c []Column) {
+	m.cols = c
+	m.updateViewport()
+}
+
+// SetWidth sets the width of the table.
+func (m *Model) SetWidth(w int) {
+	m.viewport.Width = w
+	m.updateViewport()
+}
+
+// SetHeight sets the height of the table, including the header.
+func (m *Model) SetHeight(h int) {
+	m.height = h
+	m.updateViewport()
+}
+
+// Height returns the height of the table, including the header.
+func (m Model) Height() int {
+	return m.height
+}
+
+// Width returns the width of the table.
+func (m Model) Width() int {
+	return m.viewport.Width
+}
+
+// YOffset returns the index of the first visible row.
+func (m Model) YOffset() int {
+	return m.viewport.YOffset
+}
+
+// Cursor returns the index of the selected row.
+func (m Model) Cursor() int {
+	return m.cursor
+}
+
+// SetCursor sets the cursor position in the table. The view scrolls to keep
+// the cursor visible.
+func (m *Model) SetCursor(n int) {
+	m.cursor = clamp(n, 0, len(m.rows)-1)
+	m.updateViewport()
+}
+
+// SelectedRow returns the selected row. You can cast it to your own
+// implementation.
+func (m Model) SelectedRow() Row {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return m.rows[m.cursor]
+}
+
+// MoveUp moves the selection up by any number of rows. It can not go above
+// the first row.
+func (m *Model) MoveUp(n int) {
+	m.SetCursor(m.cursor - n)
+}
+
+// MoveDown moves the selection down by any number of rows. It can not go
+// below the last row.
+func (m *Model) MoveDown(n int) {
+	m.SetCursor(m.cursor + n)
+}
+
+// GotoTop moves the selection to the first row.
+func (m *Model) GotoTop() {
+	m.SetCursor(0)
+}
+
+// GotoBottom moves the selection to the last row.
+func (m *Model) GotoBottom() {
+	m.SetCursor(len(m.rows) - 1)
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if !m.focus {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.LineUp):
+			m.MoveUp(1)
+		case key.Matches(msg, m.KeyMap.LineDown):
+			m.MoveDown(1)
+		case key.Matches(msg, m.KeyMap.PageUp):
+			m.MoveUp(m.viewport.Height)
+		case key.Matches(msg, m.KeyMap.PageDown):
+			m.MoveDown(m.viewport.Height)
+		case key.Matches(msg, m.KeyMap.HalfPageUp):
+			m.MoveUp(m.viewport.Height / 2) //nolint:gomnd
+		case key.Matches(msg, m.KeyMap.HalfPageDown):
+			m.MoveDown(m.viewport.Height / 2) //nolint:gomnd
+		case key.Matches(msg, m.KeyMap.GotoTop):
+			m.GotoTop()
+		case key.Matches(msg, m.KeyMap.GotoBottom):
+			m.GotoBottom()
+		}
+
+	case tea.MouseMsg:
+		if !m.MouseWheelEnabled {
+			break
+		}
+		switch msg.Type {
+		case tea.MouseWheelUp:
+			m.MoveUp(m.MouseWheelDelta)
+		case tea.MouseWheelDown:
+			m.MoveDown(m.MouseWheelDelta)
+		}
+	}
+
+	return m, nil
+}
+
+// ShortHelp returns bindings to show in the abbreviated help view. It's part
+// of the help.KeyMap interface.
+func (m Model) ShortHelp() []key.Binding {
+	return []key.Binding{m.KeyMap.LineUp, m.KeyMap.LineDown}
+}
+
+// FullHelp returns bindings to show the full help view. It's part of the
+// help.KeyMap interface.
+func (m Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{m.KeyMap.LineUp, m.KeyMap.LineDown, m.KeyMap.GotoTop, m.KeyMap.GotoBottom},
+		{m.KeyMap.PageUp, m.KeyMap.PageDown, m.KeyMap.HalfPageUp, m.KeyMap.HalfPageDown},
+	}
+}
+
+// View renders the component.
+func (m Model) View() string {
+	start := min(m.viewport.YOffset, len(m.rows))
+	end := min(len(m.rows), start+m.viewport.Height)
+
+	rendered := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		rendered = append(rendered, m.renderRow(i))
+	}
+
+	// Only the visible rows are rendered, so the viewport is drawn from the
+	// top of that slice.
+	vp := m.viewport
+	vp.YOffset = 0
+	vp.SetContent(strings.Join(rendered, "\n"))
+
+	return m.headersView() + "\n" + vp.View()
+}
+
+// updateViewport keeps the cursor in bounds, sizes the viewport to the space
+// beneath the header and scrolls it so that the cursor is visible.
+func (m *Model) updateViewport() {
+	m.cursor = clamp(m.cursor, 0, len(m.rows)-1)
+	m.viewport.Height = max(0, m.height-lipgloss.Height(m.headersView()))
+
+	offset := m.viewport.YOffset
+	if m.cursor < offset {
+		offset = m.cursor
+	} else if m.cursor >= offset+m.viewport.Height {
+		offset = m.cursor - m.viewport.Height + 1
+	}
+	m.viewport.YOffset = clamp(offset, 0, len(m.rows)-m.viewport.Height)
+}
+
+func (m Model) headersView() string {
+	s := make([]string, 0, len(m.cols))
+	for _, col := range m.cols {
+		s = append(s, m.Styles.Header.Render(cell(col, col.Title)))
+	}
+	return m.truncateWidth(lipgloss.JoinHorizontal(lipgloss.Left, s...))
+}
+
+func (m Model) renderRow(index int) string {
+	s := make([]string, 0, len(m.cols))
+	for i, col := range m.cols {
+		var value string
+		if i < len(m.rows[index]) {
+			value = m.rows[index][i]
+		}
+		s = append(s, m.Styles.Cell.Render(cell(col, value)))
+	}
+
+	row := lipgloss.JoinHorizontal(lipgloss.Left, s...)
+	if index == m.cursor {
+		row = m.Styles.Selected.Render(row)
+	}
+	return m.truncateWidth(row)
+}
+
+// truncateWidth cuts each line of the given string to the width of the
+// table, if a width is set.
+func (m Model) truncateWidth(s string) string {
+	if m.viewport.Width <= 0 {
+		return s
+	}
+	return lipgloss.NewStyle().MaxWidth(m.viewport.Width).Render(s)
+}
+
+// cell truncates a value to the width of the column and pads it according to
+// the column's alignment.
+func cell(col Column, value string) string {
+	value = strings.ReplaceAll(value, "\n", " ")
+	if rw.StringWidth(value) > col.Width {
+		value = rw.Truncate(value, col.Width, ellipsis)
+	}
+	return lipgloss.PlaceHorizontal(col.Width, col.Align, value)
+}
+
+// clamp restricts v to the range [low, high]. If the range is empty, as is the
+// case for a table without rows, low is returned.
+func clamp(v, low, high int) int {
+	if high < low {
+		return low
+	}
+	return min(high, max(low, v))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,1094 @@
+// Package textarea provides a multi-line text input component for Bubble Tea
+// applications. It supports soft wrapping, line numbers and vertical
+// scrolling, and shares its editing behavior with the textinput package.
+package textarea
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/internal/textedit"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	rw "github.com/mattn/go-runewidth"
+)
+
+const (
+	defaultBlinkSpeed = time.Millisecond * 530
+	defaultWidth      = 40
+	defaultHeight     = 6
+)
+
+// KeyMap defines the keybindings for the text area. The defaults mirror the
+// emacs-style bindings of the textinput component.
+type KeyMap struct {
+	CharacterForward        key.Binding
+	CharacterBackward       key.Binding
+	WordForward             key.Binding
+	WordBackward            key.Binding
+	LineNext                key.Binding
+	LinePrevious            key.Binding
+	LineStart               key.Binding
+	LineEnd                 key.Binding
+	InputBegin              key.Binding
+	InputEnd                key.Binding
+	InsertNewline           key.Binding
+	DeleteCharacterBackward key.Binding
+	DeleteCharacterForward  key.Binding
+	DeleteWordBackward      key.Binding
+	DeleteWordForward       key.Binding
+	DeleteAfterCursor       key.Binding
+	DeleteBeforeCursor      key.Binding
+	Paste                   key.Binding
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		CharacterForward: key.NewBinding(
+			key.WithKeys("right", "ctrl+f"),
+			key.WithHelp("→/ctrl+f", "character forward"),
+		),
+		CharacterBackward: key.NewBinding(
+			key.WithKeys("left", "ctrl+b"),
+			key.WithHelp("←/ctrl+b", "character backward"),
+		),
+		WordForward: key.NewBinding(
+			key.WithKeys("alt+right", "alt+f"),
+			key.WithHelp("alt+f", "word forward"),
+		),
+		WordBackward: key.NewBinding(
+			key.WithKeys("alt+left", "alt+b"),
+			key.WithHelp("alt+b", "word backward"),
+		),
+		LineNext: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓/ctrl+n", "next line"),
+		),
+		LinePrevious: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑/ctrl+p", "previous line"),
+		),
+		LineStart: key.NewBinding(
+			key.WithKeys("home", "ctrl+a"),
+			key.WithHelp("home/ctrl+a", "line start"),
+		),
+		LineEnd: key.NewBinding(
+			key.WithKeys("end", "ctrl+e"),
+			key.WithHelp("end/ctrl+e", "line end"),
+		),
+		InputBegin: key.NewBinding(
+			key.WithKeys("alt+<"),
+			key.WithHelp("alt+<", "input begin"),
+		),
+		InputEnd: key.NewBinding(
+			key.WithKeys("alt+>"),
+			key.WithHelp("alt+>", "input end"),
+		),
+		InsertNewline: key.NewBinding(
+			key.WithKeys("enter", "ctrl+m"),
+			key.WithHelp("enter", "insert newline"),
+		),
+		DeleteCharacterBackward: key.NewBinding(
+			key.WithKeys("backspace", "ctrl+h"),
+			key.WithHelp("backspace", "delete character backward"),
+		),
+		DeleteCharacterForward: key.NewBinding(
+			key.WithKeys("delete", "ctrl+d"),
+			key.WithHelp("delete", "delete character forward"),
+		),
+		DeleteWordBackward: key.NewBinding(
+			key.WithKeys("alt+backspace", "ctrl+w"),
+			key.WithHelp("ctrl+w", "delete word backward"),
+		),
+		DeleteWordForward: key.NewBinding(
+			key.WithKeys("alt+delete", "alt+d"),
+			key.WithHelp("alt+d", "delete word forward"),
+		),
+		DeleteAfterCursor: key.NewBinding(
+			key.WithKeys("ctrl+k"),
+			key.WithHelp("ctrl+k", "delete after cursor"),
+		),
+		DeleteBeforeCursor: key.NewBinding(
+			key.WithKeys("ctrl+u"),
+			key.WithHelp("ctrl+u", "delete before cursor"),
+		),
+		Paste: key.NewBinding(
+			key.WithKeys("ctrl+v"),
+			key.WithHelp("ctrl+v", "paste"),
+		),
+	}
+}
+
+// Model is the Bubble Tea model for this text area element.
+type Model struct {
+	Err error
+
+	// General settings.
+	Prompt          string
+	Placeholder     string
+	ShowLineNumbers bool
+	BlinkSpeed      time.Duration
+	EchoMode        textinput.EchoMode
+	EchoCharacter   rune
+
+	// KeyMap encodes the keybindings recognized by the text area.
+	KeyMap KeyMap
+
+	// Styles. These will be applied as inline styles.
+	//
+	// For an introduction to styling with Lip Gloss see:
+	// https://github.com/charmbracelet/lipgloss
+	PromptStyle           lipgloss.Style
+	TextStyle             lipgloss.Style
+	PlaceholderStyle      lipgloss.Style
+	CursorStyle           lipgloss.Style
+	LineNumberStyle       lipgloss.Style
+	CursorLineNumberStyle lipgloss.Style
+
+	// CharLimit is the maximum number of characters this text area will
+	// accept, including line breaks. If 0 or less, there's no limit.
+	CharLimit int
+
+	// Width is the maximum number of cells of text displayed on each line,
+	// not including the prompt and line numbers. Lines wider than this are
+	// soft wrapped. If 0 or less, lines are never wrapped.
+	Width int
+
+	// Height is the number of lines displayed at once. When the content is
+	// taller the text area scrolls to keep the cursor in view. If 0 or less
+	// the text area grows to fit its content.
+	Height int
+
+	// Validate is a function that checks whether or not the text within the
+	// text area is valid. If it is not valid, the `Err` field will be set to
+	// the error returned by the function and the edit will be discarded. If
+	// the function is not defined, all input is considered valid.
+	Validate textinput.ValidateFunc
+
+	// Times cursor blinks. It carries the ID of this Model as it relates to
+	// other textarea and textinput Models.
+	blinker textedit.Blinker
+
+	// Underlying text value, one slice of runes per line. There is always at
+	// least one line.
+	value [][]rune
+
+	// focus indicates whether user input focus should be on this input
+	// component. When false, ignore keyboard input and hide the cursor.
+	focus bool
+
+	// Cursor blink state.
+	blink bool
+
+	// Cursor position.
+	row int
+	col int
+
+	// viewport handles vertical scrolling when the content is taller than
+	// the text area.
+	viewport viewport.Model
+
+	// cursorMode determines the behavior of the cursor
+	cursorMode textinput.CursorMode
+}
+
+// New creates a new model with default settings.
+func New() Model {
+	vp := viewport.New(0, 0)
+	vp.KeyMap = viewport.KeyMap{}
+
+	return Model{
+		Prompt:                "┃ ",
+		ShowLineNumbers:       true,
+		BlinkSpeed:            defaultBlinkSpeed,
+		EchoCharacter:         '*',
+		CharLimit:             0,
+		Width:                 defaultWidth,
+		Height:                defaultHeight,
+		KeyMap:                DefaultKeyMap(),
+		PlaceholderStyle:      lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		LineNumberStyle:       lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		CursorLineNumberStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("250")),
+
+		blinker:    textedit.NewBlinker(textedit.NextID()),
+		value:      [][]rune{{}},
+		focus:      false,
+		blink:      true,
+		cursorMode: textinput.CursorBlink,
+		viewport:   vp,
+	}
+}
+
+// SetValue sets the value of the text area, replacing any existing text. The
+// cursor is placed at the end of the new value.
+func (m *Model) SetValue(s string) {
+	if m.Validate != nil {
+		if err := m.Validate(s); err != nil {
+			m.Err = err
+			return
+		}
+	}
+
+	m.Err = nil
+	m.value = [][]rune{{}}
+	m.row, m.col = 0, 0
+	m.insertRunes([]rune(s))
+	m.repositionView()
+}
+
+// InsertString inserts a string at the cursor position.
+func (m *Model) InsertString(s string) {
+	m.edit(func() {
+		m.insertRunes([]rune(s))
+	})
+	m.repositionView()
+}
+
+// InsertRune inserts a rune at the cursor position.
+func (m *Model) InsertRune(r rune) {
+	m.InsertString(string(r))
+}
+
+// Value returns the value of the text area.
+func (m Model) Value() string {
+	lines := make([]string, len(m.value))
+	for i, l := range m.value {
+		lines[i] = string(l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Length returns the number of characters currently in the text area,
+// including line breaks.
+func (m Model) Length() int {
+	var l int
+	for _, row := range m.value {
+		l += len(row)
+	}
+	return l + len(m.value) - 1
+}
+
+// LineCount returns the number of lines in the text area.
+func (m Model) LineCount() int {
+	return len(m.value)
+}
+
+// Line returns the line the cursor is on.
+func (m Model) Line() int {
+	return m.row
+}
+
+// Cursor returns the cursor's column on the current line.
+func (m Model) Cursor() int {
+	return m.col
+}
+
+// Blink returns whether or not to draw the cursor.
+func (m Model) Blink() bool {
+	return m.blink
+}
+
+// SetCursor moves the cursor to the given column on the current line. If the
+// position is out of bounds the cursor will be moved to the start or end of
+// the line accordingly. If the position is within a grapheme cluster, such as
+// an emoji sequence, the cursor is moved to the start of the cluster.
+func (m *Model) SetCursor(col int) {
+	line := m.value[m.row]
+	m.col = textedit.SnapToBoundary(line, clamp(col, 0, len(line)))
+	m.repositionView()
+}
+
+// CursorStart moves the cursor to the start of the current line.
+func (m *Model) CursorStart() {
+	m.SetCursor(0)
+}
+
+// CursorEnd moves the cursor to the end of the current line.
+func (m *Model) CursorEnd() {
+	m.SetCursor(len(m.value[m.row]))
+}
+
+// CursorDown moves the cursor down by one displayed line, which may be the
+// next soft wrapped segment of the current line. On the last line the cursor
+// moves to the end of the input.
+func (m *Model) CursorDown() {
+	m.cursorDown()
+	m.repositionView()
+}
+
+// cursorDown moves the cursor down by one displayed line.
+func (m *Model) cursorDown() {
+	segments := wrap(m.value[m.row], m.Width)
+	seg, off := cursorSegment(segments, m.col)
+	cells := rw.StringWidth(string(segments[seg][:off]))
+
+	if seg < len(segments)-1 {
+		m.col = segmentStart(segments, seg+1) + runeIndexAtCell(segments[seg+1], cells)
+		m.clampToSegment(segments, seg+1)
+		return
+	}
+
+	if m.row >= len(m.value)-1 {
+		m.col = len(m.value[m.row])
+		return
+	}
+
+	m.row++
+	next := wrap(m.value[m.row], m.Width)
+	m.col = runeIndexAtCell(next[0], cells)
+	m.clampToSegment(next, 0)
+}
+
+// CursorUp moves the cursor up by one displayed line, which may be the
+// previous soft wrapped segment of the current line. On the first line the
+// cursor moves to the start of the input.
+func (m *Model) CursorUp() {
+	m.cursorUp()
+	m.repositionView()
+}
+
+// cursorUp moves the cursor up by one displayed line.
+func (m *Model) cursorUp() {
+	segments := wrap(m.value[m.row], m.Width)
+	seg, off := cursorSegment(segments, m.col)
+	cells := rw.StringWidth(string(segments[seg][:off]))
+
+	if seg > 0 {
+		m.col = segmentStart(segments, seg-1) + runeIndexAtCell(segments[seg-1], cells)
+		m.clampToSegment(segments, seg-1)
+		return
+	}
+
+	if m.row <= 0 {
+		m.col = 0
+		return
+	}
+
+	m.row--
+	prev := wrap(m.value[m.row], m.Width)
+	last := len(prev) - 1
+	m.col = segmentStart(prev, last) + runeIndexAtCell(prev[last], cells)
+	m.clampToSegment(prev, last)
+}
+
+// clampToSegment makes sure that the cursor, after a vertical move, lands on
+// the given soft wrapped segment rather than at the start of the one
+// following it.
+func (m *Model) clampToSegment(segments [][]rune, seg int) {
+	end := segmentStart(segments, seg) + len(segments[seg])
+	if seg < len(segments)-1 && m.col >= end {
+		m.col = max(segmentStart(segments, seg), textedit.PrevBoundary(m.value[m.row], end))
+	}
+}
+
+// CursorMode returns the model's cursor mode. For available cursor modes, see
+// type textinput.CursorMode.
+func (m Model) CursorMode() textinput.CursorMode {
+	return m.cursorMode
+}
+
+// SetCursorMode sets the model's cursor mode. This method returns a command.
+//
+// For available cursor modes, see type textinput.CursorMode.
+func (m *Model) SetCursorMode(mode textinput.CursorMode) tea.Cmd {
+	m.cursorMode = mode
+	m.blink = m.cursorMode == textinput.CursorHide || !m.focus
+	if mode == textinput.CursorBlink {
+		return Blink
+	}
+	return nil
+}
+
+// Focused returns the focus state on the model.
+func (m Model) Focused() bool {
+	return m.focus
+}
+
+// Focus sets the focus state on the model. When the model is in focus it can
+// receive keyboard input and the cursor will be shown.
+func (m *Model) Focus() tea.Cmd {
+	m.focus = true
+	m.blink = m.cursorMode == textinput.CursorHide // show the cursor unless we've explicitly hidden it
+
+	if m.cursorMode == textinput.CursorBlink && m.focus {
+		return m.blinkCmd()
+	}
+	return nil
+}
+
+// Blur removes the focus state on the model. When the model is blurred it can
+// not receive keyboard input and the cursor will be hidden.
+func (m *Model) Blur() {
+	m.focus = false
+	m.blink = true
+}
+
+// Reset sets the text area to its default state with no input.
+func (m *Model) Reset() {
+	m.value = [][]rune{{}}
+	m.row, m.col = 0, 0
+	m.repositionView()
+}
+
+// edit runs an editing operation. If a Validate func is set and the result
+// of the edit is invalid, the edit is rolled back and Err is set.
+func (m *Model) edit(fn func()) {
+	if m.Validate == nil {
+		fn()
+		return
+	}
+
+	value := make([][]rune, len(m.value))
+	for i, l := range m.value {
+		value[i] = append([]rune(nil), l...)
+	}
+	row, col := m.row, m.col
+
+	fn()
+
+	if err := m.Validate(m.Value()); err != nil {
+		m.value, m.row, m.col = value, row, col
+		m.Err = err
+		return
+	}
+	m.Err = nil
+}
+
+// insertRunes inserts runes at the cursor, splitting lines on newlines. Input
+// that would exceed the CharLimit is discarded.
+func (m *Model) insertRunes(runes []rune) {
+	runes = []rune(strings.ReplaceAll(string(runes), "\r\n", "\n"))
+
+	if m.CharLimit > 0 {
+		avail := m.CharLimit - m.Length()
+		if avail <= 0 {
+			return
+		}
+		if len(runes) > avail {
+			runes = runes[:avail]
+		}
+	}
+
+	lines := splitLines(runes)
+	line := m.value[m.row]
+	head := append([]rune(nil), line[:m.col]...)
+	tail := append([]rune(nil), line[m.col:]...)
+
+	if len(lines) == 1 {
+		m.value[m.row] = append(append(head, lines[0]...), tail...)
+		m.col += len(lines[0])
+		return
+	}
+
+	rows := make([][]rune, 0, len(lines))
+	rows = append(rows, append(head, lines[0]...))
+	for _, l := range lines[1 : len(lines)-1] {
+		rows = append(rows, append([]rune(nil), l...))
+	}
+	last := lines[len(lines)-1]
+	rows = append(rows, append(append([]rune(nil), last...), tail...))
+
+	value := make([][]rune, 0, len(m.value)+len(rows)-1)
+	value = append(value, m.value[:m.row]...)
+	value = append(value, rows...)
+	value = append(value, m.value[m.row+1:]...)
+
+	m.value = value
+	m.row += len(lines) - 1
+	m.col = len(last)
+}
+
+// mergeLineBelow joins the given line with the line below it.
+func (m *Model) mergeLineBelow(row int) {
+	if row >= len(m.value)-1 {
+		return
+	}
+	m.value[row] = append(m.value[row], m.value[row+1]...)
+	m.value = append(m.value[:row+1], m.value[row+2:]...)
+}
+
+// deleteCharacterBackward deletes the character before the cursor. At the
+// start of a line it joins the line with the one above it.
+func (m *Model) deleteCharacterBackward() {
+	if m.col > 0 {
+		line := m.value[m.row]
+		prev := textedit.PrevBoundary(line, m.col)
+		m.value[m.row] = append(line[:prev], line[m.col:]...)
+		m.col = prev
+		return
+	}
+	if m.row > 0 {
+		m.col = len(m.value[m.row-1])
+		m.row--
+		m.mergeLineBelow(m.row)
+	}
+}
+
+// deleteCharacterForward deletes the character under the cursor. At the end
+// of a line it joins the line with the one below it.
+func (m *Model) deleteCharacterForward() {
+	line := m.value[m.row]
+	if m.col < len(line) {
+		m.value[m.row] = append(line[:m.col], line[textedit.NextBoundary(line, m.col):]...)
+		return
+	}
+	m.mergeLineBelow(m.row)
+}
+
+// deleteBeforeCursor deletes all text before the cursor on the current line.
+func (m *Model) deleteBeforeCursor() {
+	m.value[m.row] = m.value[m.row][m.col:]
+	m.col = 0
+}
+
+// deleteAfterCursor deletes all text after the cursor on the current line.
+// If the cursor is already at the end of the line the line break is deleted
+// instead, joining the next line onto this one.
+func (m *Model) deleteAfterCursor() {
+	if m.col >= len(m.value[m.row]) {
+		m.mergeLineBelow(m.row)
+		return
+	}
+	m.value[m.row] = m.value[m.row][:m.col]
+}
+
+// deleteWordLeft deletes the word left of the cursor. At the start of a line
+// it behaves like backspace. If input is masked delete everything before the
+// cursor so as not to reveal word breaks in the masked input.
+func (m *Model) deleteWordLeft() {
+	if m.col == 0 {
+		m.deleteCharacterBackward()
+		return
+	}
+
+	if m.EchoMode != textinput.EchoNormal {
+		m.deleteBeforeCursor()
+		return
+	}
+
+	line := m.value[m.row]
+	start := textedit.WordLeft(line, m.col)
+	m.value[m.row] = append(line[:start], line[m.col:]...)
+	m.col = start
+}
+
+// deleteWordRight deletes the word right of the cursor. At the end of a line
+// it behaves like delete. If input is masked delete everything after the
+// cursor so as not to reveal word breaks in the masked input.
+func (m *Model) deleteWordRight() {
+	line := m.value[m.row]
+	if m.col >= len(line) {
+		m.deleteCharacterForward()
+		return
+	}
+
+	if m.EchoMode != textinput.EchoNormal {
+		m.deleteAfterCursor()
+		return
+	}
+
+	end := textedit.WordRight(line, m.col)
+	m.value[m.row] = append(line[:m.col], line[end:]...)
+}
+
+// characterLeft moves the cursor one character to the left, wrapping to the
+// end of the previous line when at the start of a line.
+func (m *Model) characterLeft() {
+	if m.col > 0 {
+		m.col = textedit.PrevBoundary(m.value[m.row], m.col)
+		return
+	}
+	if m.row > 0 {
+		m.row--
+		m.col = len(m.value[m.row])
+	}
+}
+
+// characterRight moves the cursor one character to the right, wrapping to
+// the start of the next line when at the end of a line.
+func (m *Model) characterRight() {
+	if m.col < len(m.value[m.row]) {
+		m.col = textedit.NextBoundary(m.value[m.row], m.col)
+		return
+	}
+	if m.row < len(m.value)-1 {
+		m.row++
+		m.col = 0
+	}
+}
+
+// wordLeft moves the cursor one word to the left, crossing line boundaries as
+// needed. If input is masked, move to the start of the line so as not to
+// reveal word breaks in the masked input.
+func (m *Model) wordLeft() {
+	if m.EchoMode != textinput.EchoNormal {
+		m.col = 0
+		return
+	}
+
+	// Skip whitespace, treating line breaks as whitespace.
+	for m.row > 0 && isBlank(m.value[m.row][:m.col]) {
+		m.row--
+		m.col = len(m.value[m.row])
+	}
+	m.col = textedit.WordLeft(m.value[m.row], m.col)
+}
+
+// wordRight moves the cursor one word to the right, crossing line boundaries
+// as needed. If input is masked, move to the end of the line so as not to
+// reveal word breaks in the masked input.
+func (m *Model) wordRight() {
+	if m.EchoMode != textinput.EchoNormal {
+		m.col = len(m.value[m.row])
+		return
+	}
+
+	// Skip whitespace, treating line breaks as whitespace.
+	for m.row < len(m.value)-1 && isBlank(m.value[m.row][m.col:]) {
+		m.row++
+		m.col = 0
+	}
+	m.col = textedit.WordRight(m.value[m.row], m.col)
+}
+
+// handlePaste inserts pasted text at the cursor.
+func (m *Model) handlePaste(v string) {
+	m.edit(func() {
+		m.insertRunes([]rune(v))
+	})
+}
+
+// repositionView scrolls the viewport so that the cursor is visible.
+func (m *Model) repositionView() {
+	if m.Height <= 0 {
+		m.viewport.YOffset = 0
+		return
+	}
+
+	var total, cursorLine int
+	for i, l := range m.value {
+		segments := wrap(l, m.Width)
+		if i == m.row {
+			seg, _ := cursorSegment(segments, m.col)
+			cursorLine = total + seg
+		}
+		total += len(segments)
+	}
+
+	offset := m.viewport.YOffset
+	if cursorLine < offset {
+		offset = cursorLine
+	} else if cursorLine >= offset+m.Height {
+		offset = cursorLine - m.Height + 1
+	}
+	m.viewport.YOffset = clamp(offset, 0, max(0, total-m.Height))
+}
+
+func (m Model) echoTransform(v string) string {
+	switch m.EchoMode {
+	case textinput.EchoPassword:
+		return strings.Repeat(string(m.EchoCharacter), rw.StringWidth(v))
+	case textinput.EchoNone:
+		return ""
+
+	default:
+		return v
+	}
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if !m.focus {
+		m.blink = true
+		return m, nil
+	}
+
+	var resetBlink bool
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		resetBlink = true
+
+		switch {
+		case key.Matches(msg, m.KeyMap.DeleteCharacterBackward):
+			m.edit(m.deleteCharacterBackward)
+		case key.Matches(msg, m.KeyMap.DeleteCharacterForward):
+			m.edit(m.deleteCharacterForward)
+		case key.Matches(msg, m.KeyMap.DeleteWordBackward):
+			m.edit(m.deleteWordLeft)
+		case key.Matches(msg, m.KeyMap.DeleteWordForward):
+			m.edit(m.deleteWordRight)
+		case key.Matches(msg, m.KeyMap.DeleteAfterCursor):
+			m.edit(m.deleteAfterCursor)
+		case key.Matches(msg, m.KeyMap.DeleteBeforeCursor):
+			m.edit(m.deleteBeforeCursor)
+		case key.Matches(msg, m.KeyMap.InsertNewline):
+			m.edit(func() { m.insertRunes([]rune{'\n'}) })
+		case key.Matches(msg, m.KeyMap.WordBackward):
+			m.wordLeft()
+		case key.Matches(msg, m.KeyMap.WordForward):
+			m.wordRight()
+		case key.Matches(msg, m.KeyMap.CharacterBackward):
+			m.characterLeft()
+		case key.Matches(msg, m.KeyMap.CharacterForward):
+			m.characterRight()
+		case key.Matches(msg, m.KeyMap.LineNext):
+			m.CursorDown()
+		case key.Matches(msg, m.KeyMap.LinePrevious):
+			m.CursorUp()
+		case key.Matches(msg, m.KeyMap.LineStart):
+			m.CursorStart()
+		case key.Matches(msg, m.KeyMap.LineEnd):
+			m.CursorEnd()
+		case key.Matches(msg, m.KeyMap.InputBegin):
+			m.row, m.col = 0, 0
+		case key.Matches(msg, m.KeyMap.InputEnd):
+			m.row = len(m.value) - 1
+			m.CursorEnd()
+		case key.Matches(msg, m.KeyMap.Paste):
+			return m, Paste
+		default:
+			if msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace {
+				resetBlink = false
+				break
+			}
+			if msg.Alt {
+				break
+			}
+
+			// Input regular characters
+			runes := msg.Runes
+			m.edit(func() { m.insertRunes(runes) })
+		}
+
+	case textedit.InitialBlinkMsg:
+		// We accept all initialBlinkMsgs genrated by the Blink command.
+
+		if m.cursorMode != textinput.CursorBlink || !m.focus {
+			return m, nil
+		}
+
+		cmd := m.blinkCmd()
+		return m, cmd
+
+	case textedit.BlinkMsg:
+		// We're choosy about whether to accept blinkMsgs so that our cursor
+		// only exactly when it should.
+
+		// Is this model blinkable?
+		if m.cursorMode != textinput.CursorBlink || !m.focus {
+			return m, nil
+		}
+
+		// Were we expecting this blink message?
+		if !m.blinker.Expects(msg) {
+			return m, nil
+		}
+
+		m.blink = !m.blink
+		return m, m.blinkCmd()
+
+	case textedit.BlinkCanceled: // no-op
+		return m, nil
+
+	case textedit.PasteMsg:
+		m.handlePaste(string(msg))
+		resetBlink = true
+
+	case textedit.PasteErrMsg:
+		m.Err = msg
+	}
+
+	var cmd tea.Cmd
+	if resetBlink {
+		// Show the cursor unless it's been explicitly hidden
+		m.blink = m.cursorMode == textinput.CursorHide
+		cmd = m.blinkCmd()
+	}
+
+	m.repositionView()
+	return m, cmd
+}
+
+// View renders the text area in its current state.
+func (m Model) View() string {
+	// Placeholder text
+	if m.Length() == 0 && m.Placeholder != "" {
+		return m.placeholderView()
+	}
+
+	var (
+		lines     []string
+		styleText = m.TextStyle.Inline(true).Render
+	)
+
+	for row, line := range m.value {
+		segments := wrap(line, m.Width)
+
+		cursorSeg, cursorOff := -1, 0
+		if row == m.row {
+			cursorSeg, cursorOff = cursorSegment(segments, m.col)
+		}
+
+		for i, seg := range segments {
+			var (
+				b strings.Builder
+				w int
+			)
+			b.WriteString(m.gutterView(row, i))
+
+			if i == cursorSeg {
+				before := m.echoTransform(string(seg[:cursorOff]))
+				b.WriteString(styleText(before))
+				w += rw.StringWidth(before)
+
+				if cursorOff < len(seg) {
+					next := textedit.NextBoundary(seg, cursorOff)
+					under := m.echoTransform(string(seg[cursorOff:next]))
+					after := m.echoTransform(string(seg[next:]))
+					b.WriteString(m.cursorView(under)) // cursor and text under it
+					b.WriteString(styleText(after))    // text after cursor
+					w += rw.StringWidth(under) + rw.StringWidth(after)
+				} else {
+					b.WriteString(m.cursorView(" "))
+					w++
+				}
+			} else {
+				v := m.echoTransform(string(seg))
+				b.WriteString(styleText(v))
+				w += rw.StringWidth(v)
+			}
+
+			// If a max width was set fill the empty space so that background
+			// colors extend to the edge of the text area.
+			if m.Width > 0 && w < m.Width {
+				b.WriteString(styleText(strings.Repeat(" ", m.Width-w)))
+			}
+
+			lines = append(lines, b.String())
+		}
+	}
+
+	return m.scrollView(lines)
+}
+
+// placeholderView returns the prompt and placeholder view, if any.
+func (m Model) placeholderView() string {
+	var (
+		v     string
+		p     = strings.SplitN(m.Placeholder, "\n", 2)[0]
+		style = m.PlaceholderStyle.Inline(true).Render
+	)
+
+	// Cursor
+	if first, rest := textedit.FirstCluster(p); first != "" {
+		if m.blink {
+			v += m.cursorView(style(first))
+		} else {
+			v += m.cursorView(first)
+		}
+
+		// The rest of the placeholder text
+		v += style(rest)
+	}
+
+	return m.scrollView([]string{m.gutterView(0, 0) + v})
+}
+
+// scrollView fills the rendered lines up to the height of the text area and
+// renders the visible portion through the viewport.
+func (m Model) scrollView(lines []string) string {
+	for len(lines) < m.Height {
+		lines = append(lines, m.gutterView(-1, 1))
+	}
+
+	vp := m.viewport
+	vp.Height = m.Height
+	if vp.Height <= 0 {
+		vp.Height = len(lines)
+	}
+	vp.SetContent(strings.Join(lines, "\n"))
+	return vp.View()
+}
+
+// gutterView renders the prompt and, if enabled, the line number for the
+// given line. Line numbers are only drawn on the first soft wrapped segment
+// of a line.
+func (m Model) gutterView(row, segment int) string {
+	s := m.PromptStyle.Render(m.Prompt)
+	if !m.ShowLineNumbers {
+		return s
+	}
+
+	w := len(fmt.Sprint(len(m.value)))
+	if w < 3 { //nolint:gomnd
+		w = 3
+	}
+
+	if segment > 0 || row < 0 {
+		return s + m.LineNumberStyle.Render(strings.Repeat(" ", w+1))
+	}
+
+	style := m.LineNumberStyle
+	if row == m.row && m.focus {
+		style = m.CursorLineNumberStyle
+	}
+	return s + style.Render(fmt.Sprintf("%*d ", w, row+1))
+}
+
+// cursorView styles the cursor.
+func (m Model) cursorView(v string) string {
+	if m.blink {
+		return m.TextStyle.Render(v)
+	}
+	return m.CursorStyle.Inline(true).Reverse(true).Render(v)
+}
+
+// blinkCmd is an internal command used to manage cursor blinking.
+func (m *Model) blinkCmd() tea.Cmd {
+	if m.cursorMode != textinput.CursorBlink {
+		return nil
+	}
+
+	return m.blinker.Cmd(m.BlinkSpeed)
+}
+
+// Blink is a command used to initialize cursor blinking.
+func Blink() tea.Msg {
+	return textedit.Blink()
+}
+
+// Paste is a command for pasting from the clipboard into the text area.
+func Paste() tea.Msg {
+	return textedit.Paste()
+}
+
+// wrap soft wraps a line into segments no wider than the given number of
+// cells. Lines are broken after whitespace where possible; words wider than
+// the width are broken wherever they have to be, but never within a grapheme
+// cluster. The segments always cover the entire line, and a trailing empty
+// segment is added when the last one is full so that there's room to draw the
+// cursor at the end of the line.
+func wrap(runes []rune, width int) [][]rune {
+	if width <= 0 {
+		return [][]rune{runes}
+	}
+
+	var (
+		segments  [][]rune
+		start     int
+		lineWidth int
+		breakAt   = -1 // end of the last whitespace
+		bounds    = textedit.GraphemeBoundaries(runes)
+	)
+
+	for j := 0; j+1 < len(bounds); j++ {
+		i, next := bounds[j], bounds[j+1]
+		w := rw.StringWidth(string(runes[i:next]))
+		if lineWidth+w > width && i > start {
+			end := i
+			if breakAt > start {
+				end = breakAt
+			}
+			segments = append(segments, runes[start:end])
+			start = end
+			lineWidth = rw.StringWidth(string(runes[start:i]))
+			breakAt = -1
+		}
+		lineWidth += w
+		if unicode.IsSpace(runes[i]) {
+			breakAt = next
+		}
+	}
+
+	segments = append(segments, runes[start:])
+	if len(runes) > start && lineWidth >= width {
+		segments = append(segments, []rune{})
+	}
+	return segments
+}
+
+// cursorSegment returns the index of the soft wrapped segment containing the
+// given column, along with the column's offset within that segment.
+func cursorSegment(segments [][]rune, col int) (seg, offset int) {
+	for i, s := range segments {
+		if col < len(s) || i == len(segments)-1 {
+			return i, col
+		}
+		col -= len(s)
+	}
+	return 0, col
+}
+
+// segmentStart returns the column at which the given segment starts.
+func segmentStart(segments [][]rune, seg int) (col int) {
+	for _, s := range segments[:seg] {
+		col += len(s)
+	}
+	return col
+}
+
+// runeIndexAtCell returns the index of the rune starting the grapheme cluster
+// that occupies the given cell offset, or the length of the runes if the
+// offset is past the end.
+func runeIndexAtCell(runes []rune, cell int) int {
+	bounds := textedit.GraphemeBoundaries(runes)
+	var w int
+	for j := 0; j+1 < len(bounds); j++ {
+		w += rw.StringWidth(string(runes[bounds[j]:bounds[j+1]]))
+		if w > cell {
+			return bounds[j]
+		}
+	}
+	return len(runes)
+}
+
+// isBlank returns whether the runes are all whitespace.
+func isBlank(runes []rune) bool {
+	for _, r := range runes {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitLines splits runes on newlines.
+func splitLines(runes []rune) [][]rune {
+	lines := [][]rune{{}}
+	for _, r := range runes {
+		if r == '\n' {
+			lines = append(lines, []rune{})
+			continue
+		}
+		lines[len(lines)-1] = append(lines[len(lines)-1], r)
+	}
+	return lines
+}
+
+func clamp(v, low, high int) int {
+	if high < low {
+		low, high = high, low
+	}
+	return min(high, max(low, v))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
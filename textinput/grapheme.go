@@ -0,0 +1,59 @@
+package textinput
+
+import (
+	"github.com/charmbracelet/bubbles/internal/textedit"
+	rw "github.com/mattn/go-runewidth"
+)
+
+// prevBoundary returns the start of the grapheme cluster before the given
+// position. The cursor is only ever placed at grapheme cluster boundaries so
+// that emoji sequences, flags and characters with combining marks are edited
+// as a whole.
+func (m Model) prevBoundary(pos int) int {
+	return textedit.PrevBoundary(m.value, pos)
+}
+
+// nextBoundary returns the end of the grapheme cluster at the given position.
+func (m Model) nextBoundary(pos int) int {
+	return textedit.NextBoundary(m.value, pos)
+}
+
+// clusterWidth returns the width in cells of the grapheme cluster between the
+// boundaries at the given index and the next one.
+func (m Model) clusterWidth(bounds []int, i int) int {
+	return rw.StringWidth(string(m.value[bounds[i]:bounds[i+1]]))
+}
+
+// fillRight returns the end of the widest run of grapheme clusters starting
+// at the given position that fits within the input's width. At least one
+// cluster is included even if it doesn't fit.
+func (m Model) fillRight(bounds []int, start int) int {
+	end := start
+	w := 0
+	for i := textedit.BoundaryIndex(bounds, start); i+1 < len(bounds); i++ {
+		cw := m.clusterWidth(bounds, i)
+		if w+cw > m.Width && end > start {
+			break
+		}
+		w += cw
+		end = bounds[i+1]
+	}
+	return end
+}
+
+// fillLeft returns the start of the widest run of grapheme clusters ending at
+// the given position that fits within the input's width. At least one
+// cluster is included even if it doesn't fit.
+func (m Model) fillLeft(bounds []int, end int) int {
+	start := end
+	w := 0
+	for i := textedit.BoundaryIndex(bounds, end) - 1; i >= 0; i-- {
+		cw := m.clusterWidth(bounds, i)
+		if w+cw > m.Width && start < end {
+			break
+		}
+		w += cw
+		start = bounds[i]
+	}
+	return start
+}
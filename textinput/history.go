@@ -0,0 +1,222 @@
+package textinput
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// History entries are stored one per line. Backslashes and newlines within
+// entries are escaped.
+var (
+	historyEscaper   = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	historyUnescaper = strings.NewReplacer(`\\`, `\`, `\n`, "\n")
+)
+
+// AddHistory adds an entry to the end of the history, typically the value of
+// the input when it's submitted, and stops browsing the history. Empty entries
+// and entries identical to the previous one are skipped. If HistoryWriter is
+// set the entry is also written to it.
+func (m *Model) AddHistory(entry string) error {
+	defer m.resetHistory()
+
+	if entry == "" || m.HistoryLimit <= 0 {
+		return nil
+	}
+	if n := len(m.history); n > 0 && m.history[n-1] == entry {
+		return nil
+	}
+
+	m.history = append(m.history, entry)
+	m.trimHistory()
+
+	if m.HistoryWriter != nil {
+		if _, err := io.WriteString(m.HistoryWriter, historyEscaper.Replace(entry)+"\n"); err != nil {
+			return fmt.Errorf("error writing history: %w", err)
+		}
+	}
+	return nil
+}
+
+// History returns the history entries, oldest first.
+func (m Model) History() []string {
+	return m.history
+}
+
+// SetHistory replaces the history entries, oldest first.
+func (m *Model) SetHistory(entries []string) {
+	m.history = entries
+	m.trimHistory()
+	m.resetHistory()
+}
+
+// LoadHistory reads history entries from r, one per line, and appends them to
+// the history. This is the format written by SaveHistory and HistoryWriter.
+func (m *Model) LoadHistory(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			m.history = append(m.history, historyUnescaper.Replace(line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading history: %w", err)
+	}
+	m.trimHistory()
+	m.resetHistory()
+	return nil
+}
+
+// SaveHistory writes all history entries to w, one per line.
+func (m Model) SaveHistory(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, entry := range m.history {
+		if _, err := bw.WriteString(historyEscaper.Replace(entry) + "\n"); err != nil {
+			return fmt.Errorf("error writing history: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing history: %w", err)
+	}
+	return nil
+}
+
+// trimHistory drops the oldest entries that exceed the history limit.
+func (m *Model) trimHistory() {
+	if m.HistoryLimit <= 0 {
+		m.history = nil
+		return
+	}
+	if len(m.history) > m.HistoryLimit {
+		m.history = m.history[len(m.history)-m.HistoryLimit:]
+	}
+}
+
+// resetHistory stops browsing and searching the history.
+func (m *Model) resetHistory() {
+	m.historyIndex = len(m.history)
+	m.historyDraft = ""
+	m.historySearching = false
+	m.historyQuery = nil
+}
+
+// HistorySearching returns whether a reverse history search is in progress.
+func (m Model) HistorySearching() bool {
+	return m.historySearching
+}
+
+// historyPrevious replaces the value with the previous (older) history entry.
+// The value being edited is kept so it can be restored by browsing past the
+// newest entry. Returns whether or not the cursor blink should be reset.
+func (m *Model) historyPrevious() bool {
+	if m.historyIndex <= 0 || m.historyIndex > len(m.history) {
+		return false
+	}
+	if m.historyIndex == len(m.history) {
+		m.historyDraft = string(m.value)
+	}
+	m.historyIndex--
+	m.SetValue(m.history[m.historyIndex])
+	return m.cursorEnd()
+}
+
+// historyNext replaces the value with the next (newer) history entry, or the
+// value that was being edited before browsing began. Returns whether or not
+// the cursor blink should be reset.
+func (m *Model) historyNext() bool {
+	if m.historyIndex >= len(m.history) {
+		return false
+	}
+	m.historyIndex++
+	if m.historyIndex == len(m.history) {
+		m.SetValue(m.historyDraft)
+	} else {
+		m.SetValue(m.history[m.historyIndex])
+	}
+	return m.cursorEnd()
+}
+
+// startHistorySearch begins an incremental reverse search through the
+// history. Returns whether or not the cursor blink should be reset.
+func (m *Model) startHistorySearch() bool {
+	if len(m.history) == 0 {
+		return false
+	}
+	m.historySearching = true
+	m.historyFailed = false
+	m.historyQuery = nil
+	m.historyIndex = len(m.history)
+	m.historyDraft = string(m.value)
+	m.historyBefore = m.snapshot()
+	return false
+}
+
+// updateHistorySearch handles a key press during a reverse history search.
+// Returns whether the key was handled and whether or not the cursor blink
+// should be reset. Keys that aren't handled end the search, keeping the
+// matched entry as the value, and should be processed as usual.
+func (m *Model) updateHistorySearch(msg tea.KeyMsg) (bool, bool) {
+	switch {
+	case key.Matches(msg, m.KeyMap.HistorySearch):
+		return true, m.searchHistory(m.historyIndex - 1)
+	case key.Matches(msg, m.KeyMap.CancelHistorySearch):
+		before := m.historyBefore
+		m.resetHistory()
+		return true, m.restore(before)
+	case key.Matches(msg, m.KeyMap.DeleteCharacterBackward):
+		if len(m.historyQuery) > 0 {
+			m.historyQuery = m.historyQuery[:len(m.historyQuery)-1]
+		}
+		return true, m.searchHistory(len(m.history) - 1)
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+		m.historyQuery = append(m.historyQuery, msg.Runes...)
+		return true, m.searchHistory(m.historyIndex)
+	}
+
+	// Accept the match
+	m.historySearching = false
+	m.historyQuery = nil
+	m.recordEdit(m.historyBefore, editHistory)
+	return false, false
+}
+
+// searchHistory looks for the newest history entry containing the search
+// query, starting at the given index and going back in time. A match becomes
+// the value, with the cursor at the start of the matched text. Returns
+// whether or not the cursor blink should be reset.
+func (m *Model) searchHistory(from int) bool {
+	if len(m.historyQuery) == 0 {
+		m.historyFailed = false
+		m.historyIndex = len(m.history)
+		m.SetValue(m.historyDraft)
+		return m.cursorEnd()
+	}
+
+	query := string(m.historyQuery)
+	for i := min(from, len(m.history)-1); i >= 0; i-- {
+		n := strings.Index(m.history[i], query)
+		if n < 0 {
+			continue
+		}
+		m.historyFailed = false
+		m.historyIndex = i
+		m.SetValue(m.history[i])
+		return m.setCursor(len([]rune(m.history[i][:n])))
+	}
+
+	m.historyFailed = true
+	return false
+}
+
+// historySearchPrompt returns the prompt shown during a reverse history
+// search.
+func (m Model) historySearchPrompt() string {
+	if m.historyFailed {
+		return fmt.Sprintf("(failed reverse-i-search)`%s': ", string(m.historyQuery))
+	}
+	return fmt.Sprintf("(reverse-i-search)`%s': ", string(m.historyQuery))
+}
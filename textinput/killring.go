@@ -0,0 +1,92 @@
+package textinput
+
+const defaultKillRingSize = 60
+
+// pushKill adds the text removed by a kill command to the kill ring, given
+// the state from before the kill. Consecutive kills are accumulated into a
+// single entry the way readline does it: text killed backward is prepended
+// to the entry and text killed forward is appended to it.
+//
+// Nothing is saved when input is masked so as not to keep passwords around.
+func (m *Model) pushKill(before editState) {
+	n := len(before.value) - len(m.value)
+	if n <= 0 || m.EchoMode != EchoNormal || m.KillRingSize <= 0 {
+		return
+	}
+
+	start := before.pos
+	backward := m.pos < before.pos
+	if backward {
+		start = m.pos
+	}
+	killed := make([]rune, n)
+	copy(killed, before.value[start:start+n])
+
+	if m.lastEdit == editKill && len(m.killRing) > 0 {
+		last := m.killRing[len(m.killRing)-1]
+		if backward {
+			killed = append(killed, last...)
+		} else {
+			killed = append(last, killed...)
+		}
+		m.killRing[len(m.killRing)-1] = killed
+		return
+	}
+
+	m.killRing = append(m.killRing, killed)
+	if len(m.killRing) > m.KillRingSize {
+		m.killRing = m.killRing[len(m.killRing)-m.KillRingSize:]
+	}
+}
+
+// Yank inserts the most recently killed text at the cursor.
+func (m *Model) Yank() {
+	m.yank()
+}
+
+// yank inserts the most recently killed text at the cursor. Returns whether
+// or not the cursor blink should be reset.
+func (m *Model) yank() bool {
+	if len(m.killRing) == 0 {
+		return false
+	}
+	m.yankIndex = len(m.killRing) - 1
+	return m.insertYank()
+}
+
+// YankPop replaces the text inserted by the previous yank with the next older
+// entry in the kill ring, cycling back to the newest entry after the oldest.
+// It only has an effect directly after a yank.
+func (m *Model) YankPop() {
+	m.yankPop()
+}
+
+// yankPop replaces the previously yanked text with the next older entry in
+// the kill ring. Returns whether or not the cursor blink should be reset.
+func (m *Model) yankPop() bool {
+	if m.lastEdit != editYank || len(m.killRing) == 0 {
+		return false
+	}
+
+	value := make([]rune, 0, len(m.value))
+	value = append(value, m.value[:m.yankStart]...)
+	value = append(value, m.value[m.yankEnd:]...)
+	m.value = value
+	m.pos = m.yankStart
+
+	m.yankIndex--
+	if m.yankIndex < 0 {
+		m.yankIndex = len(m.killRing) - 1
+	}
+	return m.insertYank()
+}
+
+// insertYank inserts the kill ring entry at the current yank index and
+// remembers where it was placed so that a subsequent yank-pop can replace it.
+func (m *Model) insertYank() bool {
+	m.deleteSelection()
+	m.yankStart = m.pos
+	blink := m.handlePaste(string(m.killRing[m.yankIndex]))
+	m.yankEnd = m.pos
+	return blink
+}
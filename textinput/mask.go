@@ -0,0 +1,264 @@
+package textinput
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Mask placeholder characters. Any other character in a mask is a literal
+// separator, which is inserted automatically as the user types.
+const (
+	MaskDigit         = '9' // a digit
+	MaskOptionalDigit = '#' // a digit that can be skipped by typing the next literal
+	MaskLetter        = 'a' // a letter
+	MaskAlphanumeric  = '*' // a letter or a digit
+)
+
+// RuneFilter reports whether a rune may be entered into the input.
+type RuneFilter func(rune) bool
+
+// Some rune filters to choose from. You could also make your own.
+var (
+	Digits RuneFilter = func(r rune) bool {
+		return r >= '0' && r <= '9'
+	}
+	HexDigits RuneFilter = func(r rune) bool {
+		return (r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+	}
+	Letters RuneFilter = func(r rune) bool {
+		return unicode.IsLetter(r)
+	}
+	Alphanumeric RuneFilter = func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+)
+
+// NumericMode configures an input that only accepts numbers. See
+// Model.Numeric.
+type NumericMode struct {
+	// Min and Max bound the value. If Min is not less than Max the value is
+	// unbounded.
+	Min float64
+	Max float64
+
+	// Step is the amount by which the Increment and Decrement keybindings
+	// change the value. If 0 or less, the step is 1.
+	Step float64
+
+	// Precision is the number of digits allowed after the decimal point. If 0
+	// or less, only whole numbers are accepted.
+	Precision int
+}
+
+// bounded returns whether the mode has a minimum and maximum value.
+func (n NumericMode) bounded() bool {
+	return n.Min < n.Max
+}
+
+// accepts returns whether s is a number, or the beginning of one, that's
+// allowed by this mode.
+func (n NumericMode) accepts(s string) bool {
+	if strings.HasPrefix(s, "-") {
+		if n.bounded() && n.Min >= 0 {
+			return false
+		}
+		s = s[1:]
+	}
+
+	whole, frac := s, ""
+	if i := strings.IndexRune(s, '.'); i >= 0 {
+		if n.Precision <= 0 {
+			return false
+		}
+		whole, frac = s[:i], s[i+1:]
+		if len(frac) > n.Precision {
+			return false
+		}
+	}
+	for _, r := range whole + frac {
+		if !Digits(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// exceeds returns whether s is a number beyond the bounds of this mode that
+// can't be brought within them by typing more digits.
+func (n NumericMode) exceeds(s string) bool {
+	if !n.bounded() {
+		return false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return false
+	}
+	return (v > n.Max && n.Max >= 0) || (v < n.Min && n.Min <= 0)
+}
+
+// step adds the given number of steps to the number s, clamped to the bounds
+// of this mode, and formats the result according to its precision.
+func (n NumericMode) step(s string, steps float64) string {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		v = 0
+	}
+
+	size := n.Step
+	if size <= 0 {
+		size = 1
+	}
+	v += steps * size
+
+	if n.bounded() {
+		v = math.Max(n.Min, math.Min(n.Max, v))
+	}
+	return strconv.FormatFloat(v, 'f', max(0, n.Precision), 64)
+}
+
+// isMaskSlot returns whether the given mask character is a placeholder rather
+// than a literal.
+func isMaskSlot(c rune) bool {
+	switch c {
+	case MaskDigit, MaskOptionalDigit, MaskLetter, MaskAlphanumeric:
+		return true
+	}
+	return false
+}
+
+// maskSlotAccepts returns whether a rune can be entered at the given mask
+// placeholder.
+func maskSlotAccepts(c, r rune) bool {
+	switch c {
+	case MaskDigit, MaskOptionalDigit:
+		return Digits(r)
+	case MaskLetter:
+		return unicode.IsLetter(r)
+	case MaskAlphanumeric:
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	return false
+}
+
+// matchMask matches a value against the mask and returns the index in the
+// mask following the last matched character. The value may be incomplete,
+// but every character in it has to fit the mask.
+func matchMask(mask, value []rune) (int, bool) {
+	i := 0
+	for _, r := range value {
+		for {
+			if i >= len(mask) {
+				return i, false
+			}
+			c := mask[i]
+			if isMaskSlot(c) && maskSlotAccepts(c, r) || c == r {
+				i++
+				break
+			}
+			if c != MaskOptionalDigit {
+				return i, false
+			}
+			i++ // skip an optional digit
+		}
+	}
+	return i, true
+}
+
+// maskAppend adds a rune to the end of a value that matches the mask,
+// inserting literal separators and skipping optional digits as needed.
+// Returns false if the rune doesn't fit the mask.
+func maskAppend(mask, value []rune, r rune) ([]rune, bool) {
+	i, ok := matchMask(mask, value)
+	if !ok {
+		return value, false
+	}
+
+	var literals []rune
+	for ; i < len(mask); i++ {
+		c := mask[i]
+		if isMaskSlot(c) && maskSlotAccepts(c, r) || c == r {
+			return append(append(value, literals...), r), true
+		}
+		switch {
+		case c == MaskOptionalDigit:
+			continue
+		case !isMaskSlot(c):
+			literals = append(literals, c)
+		default:
+			return value, false
+		}
+	}
+	return value, false
+}
+
+// filterRunes returns the runes that may be inserted at the cursor, given the
+// input's RuneFilter, Mask and Numeric settings. Runes that aren't allowed
+// are dropped and, when a mask is set, literal separators are added.
+func (m Model) filterRunes(runes []rune) []rune {
+	return m.filterInsert(m.value[:m.pos], runes, m.value[m.pos:])
+}
+
+// sanitize returns what's left of a value that replaces the input's value
+// as a whole once it's been filtered the same way as typed runes.
+func (m Model) sanitize(runes []rune) []rune {
+	return m.filterInsert(nil, runes, nil)
+}
+
+// filterInsert returns the runes that may be inserted between before and
+// after, as filterRunes does.
+func (m Model) filterInsert(before, runes, after []rune) []rune {
+	if m.RuneFilter == nil && m.Mask == "" && m.Numeric == nil {
+		return runes
+	}
+
+	head := make([]rune, len(before), len(before)+len(runes))
+	copy(head, before)
+	tail := after
+	mask := []rune(m.Mask)
+
+	for _, r := range runes {
+		if m.RuneFilter != nil && !m.RuneFilter(r) {
+			continue
+		}
+
+		if m.Numeric != nil {
+			s := string(head) + string(r) + string(tail)
+			if !m.Numeric.accepts(s) || m.Numeric.exceeds(s) {
+				continue
+			}
+		}
+
+		if len(mask) > 0 {
+			next, ok := maskAppend(mask, head, r)
+			if !ok {
+				continue
+			}
+			if _, ok := matchMask(mask, append(append([]rune{}, next...), tail...)); !ok {
+				continue
+			}
+			head = next
+			continue
+		}
+
+		head = append(head, r)
+	}
+
+	return head[len(before):]
+}
+
+// NumericValue returns the value of a numeric input as a number.
+func (m Model) NumericValue() (float64, error) {
+	return strconv.ParseFloat(string(m.value), 64)
+}
+
+// stepNumber changes the value of a numeric input by the given number of
+// steps. Returns whether or not the cursor blink should be reset.
+func (m *Model) stepNumber(steps float64) bool {
+	if m.Numeric == nil {
+		return false
+	}
+	m.SetValue(m.Numeric.step(string(m.value), steps))
+	return m.cursorEnd()
+}
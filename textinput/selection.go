@@ -0,0 +1,138 @@
+package textinput
+
+import (
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copyErrMsg is sent when text can't be copied to the clipboard.
+type copyErrMsg struct{ error }
+
+// hasSelection returns whether any text is selected.
+func (m Model) hasSelection() bool {
+	start, end := m.selectionBounds()
+	return start < end
+}
+
+// selectionBounds returns the start and end of the selected text. If nothing
+// is selected both are the cursor position.
+func (m Model) selectionBounds() (int, int) {
+	if !m.selecting {
+		return m.pos, m.pos
+	}
+	anchor := clamp(m.selectionAnchor, 0, len(m.value))
+	return min(anchor, m.pos), max(anchor, m.pos)
+}
+
+// SelectedText returns the selected text, if any.
+func (m Model) SelectedText() string {
+	start, end := m.selectionBounds()
+	return string(m.value[start:end])
+}
+
+// SelectAll selects all text, moving the cursor to the end of the input.
+func (m *Model) SelectAll() {
+	m.selectAll()
+}
+
+// selectAll selects all text and returns whether or not the cursor blink
+// should be reset.
+func (m *Model) selectAll() bool {
+	m.selecting = true
+	m.selectionAnchor = 0
+	return m.cursorEnd()
+}
+
+// ClearSelection deselects the selected text, if any.
+func (m *Model) ClearSelection() {
+	m.selecting = false
+}
+
+// startSelection anchors a selection at the cursor if there isn't one yet.
+// Moving the cursor afterwards extends the selection.
+func (m *Model) startSelection() {
+	if !m.selecting {
+		m.selecting = true
+		m.selectionAnchor = m.pos
+	}
+}
+
+// deleteSelection deletes the selected text. Returns whether or not the
+// cursor blink should be reset.
+func (m *Model) deleteSelection() bool {
+	start, end := m.selectionBounds()
+	m.selecting = false
+	if start == end {
+		return false
+	}
+	m.value = append(m.value[:start], m.value[end:]...)
+	return m.setCursor(start)
+}
+
+// replaceSelection prepares for inserting runes by deleting the selected
+// text, as long as any of the runes would be accepted in its place. Returns
+// the runes to insert at the cursor.
+func (m *Model) replaceSelection(runes []rune) []rune {
+	if !m.hasSelection() {
+		return m.filterRunes(runes)
+	}
+
+	start, end := m.selectionBounds()
+	replaced := *m
+	replaced.value = make([]rune, 0, len(m.value)-(end-start))
+	replaced.value = append(replaced.value, m.value[:start]...)
+	replaced.value = append(replaced.value, m.value[end:]...)
+	replaced.pos = start
+
+	runes = replaced.filterRunes(runes)
+	if len(runes) > 0 {
+		m.value = replaced.value
+		m.selecting = false
+		m.setCursor(start)
+	}
+	return runes
+}
+
+// Copy returns a command that copies the selected text to the clipboard.
+// Nothing is copied when input is masked.
+func (m Model) Copy() tea.Cmd {
+	if !m.hasSelection() || m.EchoMode != EchoNormal {
+		return nil
+	}
+	return copyToClipboard(m.SelectedText())
+}
+
+// Cut deletes the selected text and returns a command that copies it to the
+// clipboard. When input is masked the text is deleted without being copied.
+func (m *Model) Cut() tea.Cmd {
+	cmd := m.Copy()
+	m.deleteSelection()
+	return cmd
+}
+
+// copyToClipboard is a command for copying text to the clipboard.
+func copyToClipboard(s string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(s); err != nil {
+			return copyErrMsg{err}
+		}
+		return nil
+	}
+}
+
+// textView renders the text between the given indexes of the value, with
+// selected text highlighted.
+func (m Model) textView(start, end int) string {
+	styleText := m.TextStyle.Inline(true).Render
+
+	selStart, selEnd := m.selectionBounds()
+	selStart = clamp(selStart, start, end)
+	selEnd = clamp(selEnd, start, end)
+
+	v := styleText(m.echoTransform(string(m.value[start:selStart])))
+	if selStart < selEnd {
+		v += m.SelectionStyle.Inline(true).Render(m.echoTransform(string(m.value[selStart:selEnd])))
+	}
+	v += styleText(m.echoTransform(string(m.value[selEnd:end])))
+	return v
+}
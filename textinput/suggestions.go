@@ -0,0 +1,149 @@
+package textinput
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/internal/textedit"
+	rw "github.com/mattn/go-runewidth"
+	"github.com/muesli/reflow/truncate"
+)
+
+// SuggestionFunc returns completion candidates for the given value, best
+// match first.
+type SuggestionFunc func(value string) []string
+
+// SetSuggestions sets a static list of suggestions. Suggestions that start
+// with the current value, ignoring case, are offered for completion in the
+// order given.
+func (m *Model) SetSuggestions(suggestions []string) {
+	m.suggestions = suggestions
+	m.refreshSuggestions()
+}
+
+// AvailableSuggestions returns the static list of suggestions.
+func (m Model) AvailableSuggestions() []string {
+	return m.suggestions
+}
+
+// MatchedSuggestions returns the suggestions offered for the current value.
+func (m Model) MatchedSuggestions() []string {
+	return m.matchedSuggestions
+}
+
+// CurrentSuggestion returns the suggestion that would be accepted, if any.
+func (m Model) CurrentSuggestion() string {
+	if m.suggestionIndex >= len(m.matchedSuggestions) {
+		return ""
+	}
+	return m.matchedSuggestions[m.suggestionIndex]
+}
+
+// NextSuggestion selects the next matched suggestion, wrapping around after
+// the last one.
+func (m *Model) NextSuggestion() {
+	if len(m.matchedSuggestions) == 0 {
+		return
+	}
+	m.suggestionIndex = (m.suggestionIndex + 1) % len(m.matchedSuggestions)
+}
+
+// PreviousSuggestion selects the previous matched suggestion, wrapping around
+// before the first one.
+func (m *Model) PreviousSuggestion() {
+	if len(m.matchedSuggestions) == 0 {
+		return
+	}
+	m.suggestionIndex--
+	if m.suggestionIndex < 0 {
+		m.suggestionIndex = len(m.matchedSuggestions) - 1
+	}
+}
+
+// canAcceptSuggestion returns whether there's a suggestion that could be
+// accepted. Suggestions are only offered with the cursor at the end of the
+// input, and not during a history search.
+func (m Model) canAcceptSuggestion() bool {
+	return m.ShowSuggestions && m.CurrentSuggestion() != "" && m.pos == len(m.value) && !m.historySearching
+}
+
+// acceptSuggestion replaces the value with the current suggestion. Returns
+// whether or not the cursor blink should be reset.
+func (m *Model) acceptSuggestion() bool {
+	if !m.canAcceptSuggestion() {
+		return false
+	}
+	m.SetValue(m.CurrentSuggestion())
+	return m.cursorEnd()
+}
+
+// updateSuggestions matches suggestions against the value if it has changed
+// since suggestions were last matched.
+func (m *Model) updateSuggestions() {
+	if m.suggestionsValue == string(m.value) && m.suggestionsShown == m.ShowSuggestions {
+		return
+	}
+	m.refreshSuggestions()
+}
+
+// refreshSuggestions matches suggestions against the current value and
+// selects the best match.
+func (m *Model) refreshSuggestions() {
+	m.suggestionsValue = string(m.value)
+	m.suggestionsShown = m.ShowSuggestions
+	m.matchedSuggestions = nil
+	m.suggestionIndex = 0
+
+	if !m.ShowSuggestions || m.EchoMode != EchoNormal || len(m.value) == 0 {
+		return
+	}
+
+	if m.SuggestionFunc != nil {
+		m.matchedSuggestions = m.SuggestionFunc(m.suggestionsValue)
+		return
+	}
+
+	value := strings.ToLower(m.suggestionsValue)
+	for _, s := range m.suggestions {
+		if len(s) > len(value) && strings.HasPrefix(strings.ToLower(s), value) {
+			m.matchedSuggestions = append(m.matchedSuggestions, s)
+		}
+	}
+}
+
+// completionView renders the cursor at the end of the input followed by the
+// rest of the current suggestion as ghost text, if it extends the value. The
+// ghost text is cut to fit within the given number of cells; if avail is 0
+// or less there's no limit. Returns the view and its width in cells.
+func (m Model) completionView(avail int) (string, int) {
+	var ghost []rune
+	if m.canAcceptSuggestion() {
+		s := []rune(m.CurrentSuggestion())
+		if len(s) > len(m.value) && strings.EqualFold(string(s[:len(m.value)]), string(m.value)) {
+			ghost = s[len(m.value):]
+		}
+	}
+
+	if len(ghost) == 0 {
+		return m.cursorView(" "), 1
+	}
+
+	style := m.CompletionStyle.Inline(true).Render
+
+	// Cursor
+	first, rest := textedit.FirstCluster(string(ghost))
+	var v string
+	if m.blink {
+		v = m.cursorView(style(first))
+	} else {
+		v = m.cursorView(first)
+	}
+	w := rw.StringWidth(first)
+
+	// The rest of the suggestion
+	if avail > 0 {
+		rest = truncate.String(rest, uint(max(0, avail-w)))
+	}
+	v += style(rest)
+
+	return v, w + rw.StringWidth(rest)
+}
@@ -1,13 +1,12 @@
 package textinput
 
 import (
-	"context"
+	"io"
 	"strings"
-	"sync"
 	"time"
-	"unicode"
 
-	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/internal/textedit"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	rw "github.com/mattn/go-runewidth"
@@ -15,38 +14,6 @@ import (
 
 const defaultBlinkSpeed = time.Millisecond * 530
 
-// Internal ID management for text inputs. Necessary for blink integrity when
-// multiple text inputs are involved.
-var (
-	lastID int
-	idMtx  sync.Mutex
-)
-
-// Return the next ID we should use on the Model.
-func nextID() int {
-	idMtx.Lock()
-	defer idMtx.Unlock()
-	lastID++
-	return lastID
-}
-
-// initialBlinkMsg initializes cursor blinking.
-type initialBlinkMsg struct{}
-
-// blinkMsg signals that the cursor should blink. It contains metadata that
-// allows us to tell if the blink message is the one we're expecting.
-type blinkMsg struct {
-	id  int
-	tag int
-}
-
-// blinkCanceled is sent when a blink operation is canceled.
-type blinkCanceled struct{}
-
-// Internal messages for clipboard operations.
-type pasteMsg string
-type pasteErrMsg struct{ error }
-
 // EchoMode sets the input behavior of the text input field.
 type EchoMode int
 
@@ -65,12 +32,6 @@ const (
 	// EchoOnEdit.
 )
 
-// blinkCtx manages cursor blinking.
-type blinkCtx struct {
-	ctx    context.Context
-	cancel context.CancelFunc
-}
-
 // CursorMode describes the behavior of the cursor.
 type CursorMode int
 
@@ -94,6 +55,184 @@ func (c CursorMode) String() string {
 // ValidateFunc is a function that returns an error if the input is invalid.
 type ValidateFunc func(string) error
 
+// KeyMap is the key bindings for different actions within the textinput. Note
+// that you don't necessarily need to use keybindings at all; the input can be
+// controlled programmatically with methods like Model.SetCursor. See the
+// GoDocs for details.
+type KeyMap struct {
+	CharacterForward        key.Binding
+	CharacterBackward       key.Binding
+	WordForward             key.Binding
+	WordBackward            key.Binding
+	DeleteWordBackward      key.Binding
+	DeleteWordForward       key.Binding
+	DeleteAfterCursor       key.Binding
+	DeleteBeforeCursor      key.Binding
+	DeleteCharacterBackward key.Binding
+	DeleteCharacterForward  key.Binding
+	LineStart               key.Binding
+	LineEnd                 key.Binding
+	Paste                   key.Binding
+	Yank                    key.Binding
+	YankPop                 key.Binding
+	Undo                    key.Binding
+	Redo                    key.Binding
+	AcceptSuggestion        key.Binding
+	NextSuggestion          key.Binding
+	PrevSuggestion          key.Binding
+	Increment               key.Binding
+	Decrement               key.Binding
+	HistoryPrevious         key.Binding
+	HistoryNext             key.Binding
+	HistorySearch           key.Binding
+	CancelHistorySearch     key.Binding
+	SelectCharacterForward  key.Binding
+	SelectCharacterBackward key.Binding
+	SelectWordForward       key.Binding
+	SelectWordBackward      key.Binding
+	SelectAll               key.Binding
+	Copy                    key.Binding
+	Cut                     key.Binding
+}
+
+// DefaultKeyMap returns a set of emacs-like default keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		CharacterForward: key.NewBinding(
+			key.WithKeys("right", "ctrl+f"),
+			key.WithHelp("→/ctrl+f", "forward"),
+		),
+		CharacterBackward: key.NewBinding(
+			key.WithKeys("left", "ctrl+b"),
+			key.WithHelp("←/ctrl+b", "back"),
+		),
+		WordForward: key.NewBinding(
+			key.WithKeys("alt+right", "alt+f"),
+			key.WithHelp("alt+f", "word forward"),
+		),
+		WordBackward: key.NewBinding(
+			key.WithKeys("alt+left", "alt+b"),
+			key.WithHelp("alt+b", "word back"),
+		),
+		DeleteWordBackward: key.NewBinding(
+			key.WithKeys("alt+backspace", "ctrl+w"),
+			key.WithHelp("ctrl+w", "delete word back"),
+		),
+		DeleteWordForward: key.NewBinding(
+			key.WithKeys("alt+d"),
+			key.WithHelp("alt+d", "delete word forward"),
+		),
+		DeleteAfterCursor: key.NewBinding(
+			key.WithKeys("ctrl+k"),
+			key.WithHelp("ctrl+k", "delete to end"),
+		),
+		DeleteBeforeCursor: key.NewBinding(
+			key.WithKeys("ctrl+u"),
+			key.WithHelp("ctrl+u", "delete to start"),
+		),
+		DeleteCharacterBackward: key.NewBinding(
+			key.WithKeys("backspace"),
+			key.WithHelp("backspace", "delete back"),
+		),
+		DeleteCharacterForward: key.NewBinding(
+			key.WithKeys("delete", "ctrl+d"),
+			key.WithHelp("delete", "delete forward"),
+		),
+		LineStart: key.NewBinding(
+			key.WithKeys("home", "ctrl+a"),
+			key.WithHelp("home/ctrl+a", "go to start"),
+		),
+		LineEnd: key.NewBinding(
+			key.WithKeys("end", "ctrl+e"),
+			key.WithHelp("end/ctrl+e", "go to end"),
+		),
+		Paste: key.NewBinding(
+			key.WithKeys("ctrl+v"),
+			key.WithHelp("ctrl+v", "paste"),
+		),
+		Yank: key.NewBinding(
+			key.WithKeys("ctrl+y"),
+			key.WithHelp("ctrl+y", "yank"),
+		),
+		YankPop: key.NewBinding(
+			key.WithKeys("alt+y"),
+			key.WithHelp("alt+y", "cycle yank"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("ctrl+z", "ctrl+_"),
+			key.WithHelp("ctrl+z", "undo"),
+		),
+		Redo: key.NewBinding(
+			key.WithKeys("alt+z"),
+			key.WithHelp("alt+z", "redo"),
+		),
+		AcceptSuggestion: key.NewBinding(
+			key.WithKeys("tab", "right"),
+			key.WithHelp("tab", "complete"),
+		),
+		NextSuggestion: key.NewBinding(
+			key.WithKeys("ctrl+n"),
+			key.WithHelp("ctrl+n", "next suggestion"),
+		),
+		PrevSuggestion: key.NewBinding(
+			key.WithKeys("ctrl+p"),
+			key.WithHelp("ctrl+p", "prev suggestion"),
+		),
+		Increment: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "increment"),
+		),
+		Decrement: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "decrement"),
+		),
+		HistoryPrevious: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "previous entry"),
+		),
+		HistoryNext: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "next entry"),
+		),
+		HistorySearch: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "search history"),
+		),
+		CancelHistorySearch: key.NewBinding(
+			key.WithKeys("esc", "ctrl+g"),
+			key.WithHelp("esc", "cancel search"),
+		),
+		SelectCharacterForward: key.NewBinding(
+			key.WithKeys("shift+right"),
+			key.WithHelp("shift+→", "select forward"),
+		),
+		SelectCharacterBackward: key.NewBinding(
+			key.WithKeys("shift+left"),
+			key.WithHelp("shift+←", "select back"),
+		),
+		SelectWordForward: key.NewBinding(
+			key.WithKeys("alt+shift+right", "ctrl+shift+right"),
+			key.WithHelp("alt+shift+→", "select word forward"),
+		),
+		SelectWordBackward: key.NewBinding(
+			key.WithKeys("alt+shift+left", "ctrl+shift+left"),
+			key.WithHelp("alt+shift+←", "select word back"),
+		),
+		SelectAll: key.NewBinding(
+			key.WithKeys("alt+a"),
+			key.WithHelp("alt+a", "select all"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("alt+c"),
+			key.WithHelp("alt+c", "copy"),
+		),
+		Cut: key.NewBinding(
+			key.WithKeys("alt+x"),
+			key.WithHelp("alt+x", "cut"),
+		),
+	}
+}
+
 // Model is the Bubble Tea model for this text input element.
 type Model struct {
 	Err error
@@ -105,6 +244,9 @@ type Model struct {
 	EchoMode      EchoMode
 	EchoCharacter rune
 
+	// KeyMap encodes the keybindings recognized by the input.
+	KeyMap KeyMap
+
 	// Styles. These will be applied as inline styles.
 	//
 	// For an introduction to styling with Lip Gloss see:
@@ -114,6 +256,8 @@ type Model struct {
 	BackgroundStyle  lipgloss.Style
 	PlaceholderStyle lipgloss.Style
 	CursorStyle      lipgloss.Style
+	CompletionStyle  lipgloss.Style
+	SelectionStyle   lipgloss.Style
 
 	// CharLimit is the maximum amount of characters this input element will
 	// accept. If 0 or less, there's no limit.
@@ -124,11 +268,52 @@ type Model struct {
 	// viewport. If 0 or less this setting is ignored.
 	Width int
 
-	// The ID of this Model as it relates to other textinput Models.
-	id int
-
-	// The ID of the blink message we're expecting to receive.
-	blinkTag int
+	// UndoLimit is the maximum number of edits kept in the undo history. If 0
+	// or less, edits can't be undone.
+	UndoLimit int
+
+	// KillRingSize is the maximum number of killed pieces of text kept for
+	// yanking. If 0 or less, killed text is discarded.
+	KillRingSize int
+
+	// ShowSuggestions enables autocompletion. The best suggestion for the
+	// current value is shown as ghost text after the cursor when the cursor
+	// is at the end of the input. Suggestions come from SuggestionFunc if
+	// it's set, and otherwise from the list given to SetSuggestions.
+	ShowSuggestions bool
+
+	// SuggestionFunc returns suggestions for the current value. Accepting a
+	// suggestion replaces the value with it; ghost text is only shown for
+	// suggestions that extend the current value.
+	SuggestionFunc SuggestionFunc
+
+	// RuneFilter, if set, restricts which characters can be entered into
+	// the input, whether they're typed, pasted or set. Other characters are
+	// dropped.
+	RuneFilter RuneFilter
+
+	// Mask, if set, restricts input to a fixed format such as "99/99/9999"
+	// for a date or "###.###.###.###" for an IPv4 address. See MaskDigit and
+	// friends for the placeholder characters; every other character is a
+	// literal separator that's inserted automatically as the user types.
+	Mask string
+
+	// Numeric, if set, restricts input to numbers and lets the user step the
+	// value with the Increment and Decrement keybindings.
+	Numeric *NumericMode
+
+	// HistoryLimit is the maximum number of entries kept in the history. If 0
+	// or less, which is the default, no history is kept and the history
+	// keybindings do nothing. Entries are added with AddHistory.
+	HistoryLimit int
+
+	// HistoryWriter, if set, receives each entry added to the history, so
+	// that it can be persisted and loaded later with LoadHistory.
+	HistoryWriter io.Writer
+
+	// Times cursor blinks. It carries the ID of this Model as it relates to
+	// other textinput and textarea Models.
+	blinker textedit.Blinker
 
 	// Underlying text value.
 	value []rune
@@ -148,12 +333,43 @@ type Model struct {
 	offset      int
 	offsetRight int
 
-	// Used to manage cursor blink
-	blinkCtx *blinkCtx
-
 	// cursorMode determines the behavior of the cursor
 	cursorMode CursorMode
 
+	// Undo and redo history.
+	undoStack []editState
+	redoStack []editState
+	lastEdit  editKind
+
+	// Killed text available for yanking, oldest first, and the bounds of the
+	// most recently yanked text.
+	killRing  [][]rune
+	yankIndex int
+	yankStart int
+	yankEnd   int
+
+	// Autocompletion state. Suggestions are matched against the value they
+	// were last computed for.
+	suggestions        []string
+	matchedSuggestions []string
+	suggestionIndex    int
+	suggestionsValue   string
+	suggestionsShown   bool
+
+	// History of submitted values, oldest first, and the state of browsing
+	// and searching it. historyIndex is len(history) when not browsing.
+	history          []string
+	historyIndex     int
+	historyDraft     string
+	historySearching bool
+	historyFailed    bool
+	historyQuery     []rune
+	historyBefore    editState
+
+	// Selected text runs between the anchor and the cursor.
+	selecting       bool
+	selectionAnchor int
+
 	// Validate is a function that checks whether or not the text within the
 	// input is valid. If it is not valid, the `Err` field will be set to the
 	// error returned by the function. If the function is not defined, all
@@ -168,18 +384,19 @@ func New() Model {
 		BlinkSpeed:       defaultBlinkSpeed,
 		EchoCharacter:    '*',
 		CharLimit:        0,
+		KeyMap:           DefaultKeyMap(),
+		UndoLimit:        defaultUndoLimit,
+		KillRingSize:     defaultKillRingSize,
 		PlaceholderStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		CompletionStyle:  lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		SelectionStyle:   lipgloss.NewStyle().Background(lipgloss.Color("240")),
 
-		id:         nextID(),
+		blinker:    textedit.NewBlinker(textedit.NextID()),
 		value:      nil,
 		focus:      false,
 		blink:      true,
 		pos:        0,
 		cursorMode: CursorBlink,
-
-		blinkCtx: &blinkCtx{
-			ctx: context.Background(),
-		},
 	}
 }
 
@@ -188,10 +405,13 @@ func New() Model {
 // Deprecated. Use New instead.
 var NewModel = New
 
-// SetValue sets the value of the text input.
+// SetValue sets the value of the text input. The value is filtered by
+// RuneFilter, Mask and Numeric as if it had been typed, so characters they
+// don't allow are dropped.
 func (m *Model) SetValue(s string) {
+	runes := m.sanitize([]rune(s))
 	if m.Validate != nil {
-		if err := m.Validate(s); err != nil {
+		if err := m.Validate(string(runes)); err != nil {
 			m.Err = err
 			return
 		}
@@ -199,7 +419,6 @@ func (m *Model) SetValue(s string) {
 
 	m.Err = nil
 
-	runes := []rune(s)
 	if m.CharLimit > 0 && len(runes) > m.CharLimit {
 		m.value = runes[:m.CharLimit]
 	} else {
@@ -209,6 +428,7 @@ func (m *Model) SetValue(s string) {
 		m.setCursor(len(m.value))
 	}
 	m.handleOverflow()
+	m.updateSuggestions()
 }
 
 // Value returns the value of the text input.
@@ -227,9 +447,11 @@ func (m Model) Blink() bool {
 }
 
 // SetCursor moves the cursor to the given position. If the position is
-// out of bounds the cursor will be moved to the start or end accordingly.
+// out of bounds the cursor will be moved to the start or end accordingly. If
+// the position is within a grapheme cluster, such as an emoji sequence, the
+// cursor is moved to the start of the cluster.
 func (m *Model) SetCursor(pos int) {
-	m.setCursor(pos)
+	m.setCursor(textedit.SnapToBoundary(m.value, pos))
 }
 
 // setCursor moves the cursor to the given position and returns whether or not
@@ -314,13 +536,14 @@ func (m *Model) Blur() {
 // or not the cursor blink should reset.
 func (m *Model) Reset() bool {
 	m.value = nil
+	m.selecting = false
 	return m.setCursor(0)
 }
 
 // handle a clipboard paste event, if supported. Returns whether or not the
 // cursor blink should reset.
 func (m *Model) handlePaste(v string) bool {
-	paste := []rune(v)
+	paste := m.replaceSelection([]rune(v))
 
 	var availSpace int
 	if m.CharLimit > 0 {
@@ -371,7 +594,9 @@ func (m *Model) handlePaste(v string) bool {
 }
 
 // If a max width is defined, perform some logic to treat the visible area
-// as a horizontally scrolling viewport.
+// as a horizontally scrolling viewport. The visible area always starts and
+// ends on grapheme cluster boundaries and is measured in cells, so wide
+// characters such as CJK and emoji are accounted for.
 func (m *Model) handleOverflow() {
 	if m.Width <= 0 || rw.StringWidth(string(m.value)) <= m.Width {
 		m.offset = 0
@@ -379,39 +604,24 @@ func (m *Model) handleOverflow() {
 		return
 	}
 
-	// Correct right offset if we've deleted characters
-	m.offsetRight = min(m.offsetRight, len(m.value))
+	bounds := textedit.GraphemeBoundaries(m.value)
 
+	// Scroll left if the cursor is before the visible area, and otherwise
+	// show as much as fits from where the visible area currently starts.
+	m.offset = min(m.offset, len(m.value))
 	if m.pos < m.offset {
 		m.offset = m.pos
+	}
+	m.offsetRight = m.fillRight(bounds, m.offset)
 
-		w := 0
-		i := 0
-		runes := m.value[m.offset:]
-
-		for i < len(runes) && w <= m.Width {
-			w += rw.RuneWidth(runes[i])
-			if w <= m.Width+1 {
-				i++
-			}
-		}
-
-		m.offsetRight = m.offset + i
-	} else if m.pos >= m.offsetRight {
-		m.offsetRight = m.pos
-
-		w := 0
-		runes := m.value[:m.offsetRight]
-		i := len(runes) - 1
-
-		for i > 0 && w < m.Width {
-			w += rw.RuneWidth(runes[i])
-			if w <= m.Width {
-				i--
-			}
+	// Scroll right if the cursor is after the visible area. A cursor at the
+	// end of the input is drawn in the cell after the visible area.
+	if m.pos > m.offsetRight || (m.pos == m.offsetRight && m.pos < len(m.value)) {
+		m.offsetRight = len(m.value)
+		if m.pos < len(m.value) {
+			m.offsetRight = m.nextBoundary(m.pos)
 		}
-
-		m.offset = m.offsetRight - (len(runes) - 1 - i)
+		m.offset = m.fillLeft(bounds, m.offsetRight)
 	}
 }
 
@@ -442,39 +652,9 @@ func (m *Model) deleteWordLeft() bool {
 		return m.deleteBeforeCursor()
 	}
 
-	// Linter note: it's critical that we acquire the initial cursor position
-	// here prior to altering it via SetCursor() below. As such, moving this
-	// call into the corresponding if clause does not apply here.
-	oldPos := m.pos //nolint:ifshort
-
-	blink := m.setCursor(m.pos - 1)
-	for unicode.IsSpace(m.value[m.pos]) {
-		if m.pos <= 0 {
-			break
-		}
-		// ignore series of whitespace before cursor
-		blink = m.setCursor(m.pos - 1)
-	}
-
-	for m.pos > 0 {
-		if !unicode.IsSpace(m.value[m.pos]) {
-			blink = m.setCursor(m.pos - 1)
-		} else {
-			if m.pos > 0 {
-				// keep the previous space
-				blink = m.setCursor(m.pos + 1)
-			}
-			break
-		}
-	}
-
-	if oldPos > len(m.value) {
-		m.value = m.value[:m.pos]
-	} else {
-		m.value = append(m.value[:m.pos], m.value[oldPos:]...)
-	}
-
-	return blink
+	start := textedit.WordLeft(m.value, m.pos)
+	m.value = append(m.value[:start], m.value[m.pos:]...)
+	return m.setCursor(start)
 }
 
 // deleteWordRight deletes the word right to the cursor. Returns whether or not
@@ -489,32 +669,9 @@ func (m *Model) deleteWordRight() bool {
 		return m.deleteAfterCursor()
 	}
 
-	oldPos := m.pos
-	m.setCursor(m.pos + 1)
-	for unicode.IsSpace(m.value[m.pos]) {
-		// ignore series of whitespace after cursor
-		m.setCursor(m.pos + 1)
-
-		if m.pos >= len(m.value) {
-			break
-		}
-	}
-
-	for m.pos < len(m.value) {
-		if !unicode.IsSpace(m.value[m.pos]) {
-			m.setCursor(m.pos + 1)
-		} else {
-			break
-		}
-	}
-
-	if m.pos > len(m.value) {
-		m.value = m.value[:oldPos]
-	} else {
-		m.value = append(m.value[:oldPos], m.value[m.pos:]...)
-	}
-
-	return m.setCursor(oldPos)
+	end := textedit.WordRight(m.value, m.pos)
+	m.value = append(m.value[:m.pos], m.value[end:]...)
+	return m.setCursor(m.pos)
 }
 
 // wordLeft moves the cursor one word to the left. Returns whether or not the
@@ -529,27 +686,7 @@ func (m *Model) wordLeft() bool {
 		return m.cursorStart()
 	}
 
-	blink := false
-	i := m.pos - 1
-	for i >= 0 {
-		if unicode.IsSpace(m.value[i]) {
-			blink = m.setCursor(m.pos - 1)
-			i--
-		} else {
-			break
-		}
-	}
-
-	for i >= 0 {
-		if !unicode.IsSpace(m.value[i]) {
-			blink = m.setCursor(m.pos - 1)
-			i--
-		} else {
-			break
-		}
-	}
-
-	return blink
+	return m.setCursor(textedit.WordLeft(m.value, m.pos))
 }
 
 // wordRight moves the cursor one word to the right. Returns whether or not the
@@ -564,27 +701,7 @@ func (m *Model) wordRight() bool {
 		return m.cursorEnd()
 	}
 
-	blink := false
-	i := m.pos
-	for i < len(m.value) {
-		if unicode.IsSpace(m.value[i]) {
-			blink = m.setCursor(m.pos + 1)
-			i++
-		} else {
-			break
-		}
-	}
-
-	for i < len(m.value) {
-		if !unicode.IsSpace(m.value[i]) {
-			blink = m.setCursor(m.pos + 1)
-			i++
-		} else {
-			break
-		}
-	}
-
-	return blink
+	return m.setCursor(textedit.WordRight(m.value, m.pos))
 }
 
 func (m Model) echoTransform(v string) string {
@@ -606,75 +723,143 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, nil
 	}
 
-	var resetBlink bool
+	var (
+		resetBlink bool
+		cmd        tea.Cmd
+	)
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyBackspace: // delete character before cursor
-			m.Err = nil
-
-			if msg.Alt {
-				resetBlink = m.deleteWordLeft()
-			} else {
-				if len(m.value) > 0 {
-					m.value = append(m.value[:max(0, m.pos-1)], m.value[m.pos:]...)
-					if m.pos > 0 {
-						resetBlink = m.setCursor(m.pos - 1)
-					}
-				}
-			}
-		case tea.KeyLeft, tea.KeyCtrlB:
-			if msg.Alt { // alt+left arrow, back one word
-				resetBlink = m.wordLeft()
+		if m.historySearching {
+			var handled bool
+			handled, resetBlink = m.updateHistorySearch(msg)
+			if handled {
 				break
 			}
-			if m.pos > 0 { // left arrow, ^F, back one character
-				resetBlink = m.setCursor(m.pos - 1)
+		}
+
+		before := m.snapshot()
+		edit := editNone
+		keepSelection := false
+
+		switch {
+		case key.Matches(msg, m.KeyMap.AcceptSuggestion) && m.canAcceptSuggestion():
+			edit = editComplete
+			resetBlink = m.acceptSuggestion()
+		case key.Matches(msg, m.KeyMap.NextSuggestion):
+			m.NextSuggestion()
+		case key.Matches(msg, m.KeyMap.PrevSuggestion):
+			m.PreviousSuggestion()
+		case key.Matches(msg, m.KeyMap.DeleteWordBackward):
+			m.Err = nil
+			edit = editKill
+			resetBlink = m.deleteWordLeft()
+			m.pushKill(before)
+		case key.Matches(msg, m.KeyMap.DeleteCharacterBackward):
+			m.Err = nil
+			edit = editDelete
+			if m.hasSelection() {
+				resetBlink = m.deleteSelection()
+			} else if m.pos > 0 {
+				prev := m.prevBoundary(m.pos)
+				m.value = append(m.value[:prev], m.value[m.pos:]...)
+				resetBlink = m.setCursor(prev)
 			}
-		case tea.KeyRight, tea.KeyCtrlF:
-			if msg.Alt { // alt+right arrow, forward one word
-				resetBlink = m.wordRight()
-				break
+		case key.Matches(msg, m.KeyMap.WordBackward):
+			resetBlink = m.wordLeft()
+		case key.Matches(msg, m.KeyMap.CharacterBackward):
+			if m.pos > 0 {
+				resetBlink = m.setCursor(m.prevBoundary(m.pos))
 			}
-			if m.pos < len(m.value) { // right arrow, ^F, forward one character
-				resetBlink = m.setCursor(m.pos + 1)
+		case key.Matches(msg, m.KeyMap.WordForward):
+			resetBlink = m.wordRight()
+		case key.Matches(msg, m.KeyMap.CharacterForward):
+			if m.pos < len(m.value) {
+				resetBlink = m.setCursor(m.nextBoundary(m.pos))
 			}
-		case tea.KeyCtrlW: // ^W, delete word left of cursor
-			resetBlink = m.deleteWordLeft()
-		case tea.KeyHome, tea.KeyCtrlA: // ^A, go to beginning
+		case key.Matches(msg, m.KeyMap.LineStart):
 			resetBlink = m.cursorStart()
-		case tea.KeyDelete, tea.KeyCtrlD: // ^D, delete char under cursor
-			if len(m.value) > 0 && m.pos < len(m.value) {
-				m.value = append(m.value[:m.pos], m.value[m.pos+1:]...)
+		case key.Matches(msg, m.KeyMap.DeleteCharacterForward):
+			edit = editDelete
+			if m.hasSelection() {
+				resetBlink = m.deleteSelection()
+			} else if m.pos < len(m.value) {
+				m.value = append(m.value[:m.pos], m.value[m.nextBoundary(m.pos):]...)
 			}
-		case tea.KeyCtrlE, tea.KeyEnd: // ^E, go to end
+		case key.Matches(msg, m.KeyMap.LineEnd):
 			resetBlink = m.cursorEnd()
-		case tea.KeyCtrlK: // ^K, kill text after cursor
+		case key.Matches(msg, m.KeyMap.DeleteAfterCursor):
+			edit = editKill
 			resetBlink = m.deleteAfterCursor()
-		case tea.KeyCtrlU: // ^U, kill text before cursor
+			m.pushKill(before)
+		case key.Matches(msg, m.KeyMap.DeleteBeforeCursor):
+			edit = editKill
 			resetBlink = m.deleteBeforeCursor()
-		case tea.KeyCtrlV: // ^V paste
+			m.pushKill(before)
+		case key.Matches(msg, m.KeyMap.DeleteWordForward):
+			edit = editKill
+			resetBlink = m.deleteWordRight()
+			m.pushKill(before)
+		case key.Matches(msg, m.KeyMap.Paste):
 			return m, Paste
-		case tea.KeyRunes, tea.KeySpace: // input regular characters
-			if msg.Alt && len(msg.Runes) == 1 {
-				if msg.Runes[0] == 'd' { // alt+d, delete word right of cursor
-					resetBlink = m.deleteWordRight()
-					break
-				}
-				if msg.Runes[0] == 'b' { // alt+b, back one word
-					resetBlink = m.wordLeft()
-					break
-				}
-				if msg.Runes[0] == 'f' { // alt+f, forward one word
-					resetBlink = m.wordRight()
-					break
-				}
+		case key.Matches(msg, m.KeyMap.Yank):
+			edit = editYank
+			resetBlink = m.yank()
+		case key.Matches(msg, m.KeyMap.YankPop):
+			edit = editYank
+			resetBlink = m.yankPop()
+		case key.Matches(msg, m.KeyMap.Undo):
+			resetBlink = m.undo()
+		case key.Matches(msg, m.KeyMap.Redo):
+			resetBlink = m.redo()
+		case key.Matches(msg, m.KeyMap.Increment) && m.Numeric != nil:
+			edit = editStep
+			resetBlink = m.stepNumber(1)
+		case key.Matches(msg, m.KeyMap.Decrement) && m.Numeric != nil:
+			edit = editStep
+			resetBlink = m.stepNumber(-1)
+		case key.Matches(msg, m.KeyMap.HistoryPrevious):
+			edit = editHistory
+			resetBlink = m.historyPrevious()
+		case key.Matches(msg, m.KeyMap.HistoryNext):
+			edit = editHistory
+			resetBlink = m.historyNext()
+		case key.Matches(msg, m.KeyMap.HistorySearch):
+			resetBlink = m.startHistorySearch()
+		case key.Matches(msg, m.KeyMap.SelectCharacterForward):
+			keepSelection = true
+			m.startSelection()
+			resetBlink = m.setCursor(m.nextBoundary(m.pos))
+		case key.Matches(msg, m.KeyMap.SelectCharacterBackward):
+			keepSelection = true
+			m.startSelection()
+			resetBlink = m.setCursor(m.prevBoundary(m.pos))
+		case key.Matches(msg, m.KeyMap.SelectWordForward):
+			keepSelection = true
+			m.startSelection()
+			resetBlink = m.wordRight()
+		case key.Matches(msg, m.KeyMap.SelectWordBackward):
+			keepSelection = true
+			m.startSelection()
+			resetBlink = m.wordLeft()
+		case key.Matches(msg, m.KeyMap.SelectAll):
+			keepSelection = true
+			resetBlink = m.selectAll()
+		case key.Matches(msg, m.KeyMap.Copy):
+			keepSelection = true
+			cmd = m.Copy()
+		case key.Matches(msg, m.KeyMap.Cut):
+			edit = editCut
+			cmd = m.Cut()
+		default:
+			if msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace {
+				break
 			}
 
 			// Input a regular character
-			if m.CharLimit <= 0 || len(m.value) < m.CharLimit {
-				runes := msg.Runes
+			edit = editInsert
+			runes := m.replaceSelection(msg.Runes)
+			if len(runes) > 0 && (m.CharLimit <= 0 || len(m.value) < m.CharLimit) {
 
 				value := make([]rune, len(m.value))
 				copy(value, m.value)
@@ -686,7 +871,12 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		}
 
-	case initialBlinkMsg:
+		if !keepSelection {
+			m.selecting = false
+		}
+		m.recordEdit(before, edit)
+
+	case textedit.InitialBlinkMsg:
 		// We accept all initialBlinkMsgs genrated by the Blink command.
 
 		if m.cursorMode != CursorBlink || !m.focus {
@@ -696,7 +886,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		cmd := m.blinkCmd()
 		return m, cmd
 
-	case blinkMsg:
+	case textedit.BlinkMsg:
 		// We're choosy about whether to accept blinkMsgs so that our cursor
 		// only exactly when it should.
 
@@ -706,7 +896,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 
 		// Were we expecting this blink message?
-		if msg.id != m.id || msg.tag != m.blinkTag {
+		if !m.blinker.Expects(msg) {
 			return m, nil
 		}
 
@@ -717,25 +907,104 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 		return m, cmd
 
-	case blinkCanceled: // no-op
+	case textedit.BlinkCanceled: // no-op
 		return m, nil
 
-	case pasteMsg:
+	case textedit.PasteMsg:
+		before := m.snapshot()
 		resetBlink = m.handlePaste(string(msg))
+		m.recordEdit(before, editPaste)
+
+	case textedit.PasteErrMsg:
+		m.Err = msg
 
-	case pasteErrMsg:
+	case copyErrMsg:
 		m.Err = msg
 	}
 
-	var cmd tea.Cmd
 	if resetBlink {
-		cmd = m.blinkCmd()
+		if cmd != nil {
+			cmd = tea.Batch(cmd, m.blinkCmd())
+		} else {
+			cmd = m.blinkCmd()
+		}
 	}
 
 	m.handleOverflow()
+	m.updateSuggestions()
 	return m, cmd
 }
 
+// ShortHelp returns bindings to show in the abbreviated help view. It's part
+// of the help.KeyMap interface.
+func (m Model) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.KeyMap.WordBackward,
+		m.KeyMap.WordForward,
+		m.KeyMap.DeleteWordBackward,
+		m.KeyMap.Paste,
+	}
+}
+
+// FullHelp returns bindings to show the full help view. It's part of the
+// help.KeyMap interface.
+func (m Model) FullHelp() [][]key.Binding {
+	bindings := [][]key.Binding{
+		{
+			m.KeyMap.CharacterForward,
+			m.KeyMap.CharacterBackward,
+			m.KeyMap.WordForward,
+			m.KeyMap.WordBackward,
+			m.KeyMap.LineStart,
+			m.KeyMap.LineEnd,
+		},
+		{
+			m.KeyMap.DeleteCharacterBackward,
+			m.KeyMap.DeleteCharacterForward,
+			m.KeyMap.DeleteWordBackward,
+			m.KeyMap.DeleteWordForward,
+			m.KeyMap.DeleteAfterCursor,
+			m.KeyMap.DeleteBeforeCursor,
+		},
+		{
+			m.KeyMap.AcceptSuggestion,
+			m.KeyMap.NextSuggestion,
+			m.KeyMap.PrevSuggestion,
+		},
+		{
+			m.KeyMap.Paste,
+			m.KeyMap.Yank,
+			m.KeyMap.YankPop,
+			m.KeyMap.Undo,
+			m.KeyMap.Redo,
+		},
+		{
+			m.KeyMap.SelectCharacterForward,
+			m.KeyMap.SelectCharacterBackward,
+			m.KeyMap.SelectWordForward,
+			m.KeyMap.SelectWordBackward,
+			m.KeyMap.SelectAll,
+			m.KeyMap.Copy,
+			m.KeyMap.Cut,
+		},
+	}
+	if m.HistoryLimit > 0 {
+		bindings = append(bindings, []key.Binding{
+			m.KeyMap.HistoryPrevious,
+			m.KeyMap.HistoryNext,
+			m.KeyMap.HistorySearch,
+			m.KeyMap.CancelHistorySearch,
+		})
+	}
+	if m.Numeric != nil {
+		bindings = append(bindings, []key.Binding{
+			m.KeyMap.Increment,
+			m.KeyMap.Decrement,
+		})
+	}
+	return bindings
+}
+
 // View renders the textinput in its current state.
 func (m Model) View() string {
 	// Placeholder text
@@ -747,27 +1016,36 @@ func (m Model) View() string {
 
 	value := m.value[m.offset:m.offsetRight]
 	pos := max(0, m.pos-m.offset)
-	v := styleText(m.echoTransform(string(value[:pos])))
+	v := m.textView(m.offset, m.offset+pos)
 
+	var completionWidth int
 	if pos < len(value) {
-		v += m.cursorView(m.echoTransform(string(value[pos]))) // cursor and text under it
-		v += styleText(m.echoTransform(string(value[pos+1:]))) // text after cursor
+		next := m.nextBoundary(m.pos)
+		v += m.cursorView(m.echoTransform(string(m.value[m.pos:next]))) // cursor and text under it
+		v += m.textView(next, m.offsetRight)                            // text after cursor
 	} else {
-		v += m.cursorView(" ")
+		// Cursor, followed by the current suggestion if there is one
+		var avail int
+		if m.Width > 0 {
+			avail = m.Width + 1 - rw.StringWidth(string(value))
+		}
+		completion, w := m.completionView(avail)
+		v += completion
+		completionWidth = w - 1
 	}
 
 	// If a max width and background color were set fill the empty spaces with
 	// the background color.
 	valWidth := rw.StringWidth(string(value))
 	if m.Width > 0 && valWidth <= m.Width {
-		padding := max(0, m.Width-valWidth)
+		padding := max(0, m.Width-valWidth-completionWidth)
 		if valWidth+padding <= m.Width && pos < len(value) {
 			padding++
 		}
 		v += styleText(strings.Repeat(" ", padding))
 	}
 
-	return m.PromptStyle.Render(m.Prompt) + v
+	return m.promptView() + v
 }
 
 // placeholderView returns the prompt and placeholder view, if any.
@@ -779,16 +1057,26 @@ func (m Model) placeholderView() string {
 	)
 
 	// Cursor
+	first, rest := textedit.FirstCluster(p)
 	if m.blink {
-		v += m.cursorView(style(p[:1]))
+		v += m.cursorView(style(first))
 	} else {
-		v += m.cursorView(p[:1])
+		v += m.cursorView(first)
 	}
 
 	// The rest of the placeholder text
-	v += style(p[1:])
+	v += style(rest)
+
+	return m.promptView() + v
+}
 
-	return m.PromptStyle.Render(m.Prompt) + v
+// promptView returns the styled prompt. During a history search the prompt
+// shows the search query instead.
+func (m Model) promptView() string {
+	if m.historySearching {
+		return m.PromptStyle.Render(m.historySearchPrompt())
+	}
+	return m.PromptStyle.Render(m.Prompt)
 }
 
 // cursorView styles the cursor.
@@ -805,37 +1093,17 @@ func (m *Model) blinkCmd() tea.Cmd {
 		return nil
 	}
 
-	if m.blinkCtx != nil && m.blinkCtx.cancel != nil {
-		m.blinkCtx.cancel()
-	}
-
-	ctx, cancel := context.WithTimeout(m.blinkCtx.ctx, m.BlinkSpeed)
-	m.blinkCtx.cancel = cancel
-
-	m.blinkTag++
-
-	return func() tea.Msg {
-		defer cancel()
-		<-ctx.Done()
-		if ctx.Err() == context.DeadlineExceeded {
-			return blinkMsg{id: m.id, tag: m.blinkTag}
-		}
-		return blinkCanceled{}
-	}
+	return m.blinker.Cmd(m.BlinkSpeed)
 }
 
 // Blink is a command used to initialize cursor blinking.
 func Blink() tea.Msg {
-	return initialBlinkMsg{}
+	return textedit.Blink()
 }
 
 // Paste is a command for pasting from the clipboard into the text input.
 func Paste() tea.Msg {
-	str, err := clipboard.ReadAll()
-	if err != nil {
-		return pasteErrMsg{err}
-	}
-	return pasteMsg(str)
+	return textedit.Paste()
 }
 
 func clamp(v, low, high int) int {
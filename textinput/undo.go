@@ -0,0 +1,130 @@
+package textinput
+
+const defaultUndoLimit = 100
+
+// editKind describes the type of an edit for the purposes of the undo history.
+type editKind int
+
+const (
+	// editNone is used for anything that isn't an edit, such as cursor
+	// movement. It ends the current group of edits.
+	editNone editKind = iota
+
+	// Typing, single character deletions and numeric steps. Consecutive
+	// edits of the same kind are grouped into one undo step.
+	editInsert
+	editDelete
+	editStep
+
+	// Kills, cuts, pastes, yanks, accepted suggestions and recalled history
+	// entries. Each of these is always its own undo step.
+	editKill
+	editCut
+	editPaste
+	editYank
+	editComplete
+	editHistory
+)
+
+// grouped returns whether consecutive edits of this kind should be undone
+// together.
+func (k editKind) grouped() bool {
+	return k == editInsert || k == editDelete || k == editStep
+}
+
+// editState is a snapshot of the input's value and cursor position.
+type editState struct {
+	value []rune
+	pos   int
+}
+
+// snapshot returns a copy of the current value and cursor position.
+func (m Model) snapshot() editState {
+	value := make([]rune, len(m.value))
+	copy(value, m.value)
+	return editState{value: value, pos: m.pos}
+}
+
+// recordEdit adds the state from before an edit to the undo history. Nothing
+// is recorded if the edit didn't change the value, and consecutive edits of a
+// grouped kind share the undo step created by the first of them.
+func (m *Model) recordEdit(before editState, kind editKind) {
+	if kind == editNone {
+		m.lastEdit = editNone
+		return
+	}
+
+	if string(before.value) == string(m.value) {
+		return
+	}
+
+	// Any new edit invalidates the redo history.
+	m.redoStack = nil
+
+	if kind.grouped() && kind == m.lastEdit {
+		return
+	}
+	m.lastEdit = kind
+
+	if m.UndoLimit <= 0 {
+		m.undoStack = nil
+		return
+	}
+
+	m.undoStack = append(m.undoStack, before)
+	if len(m.undoStack) > m.UndoLimit {
+		m.undoStack = m.undoStack[len(m.undoStack)-m.UndoLimit:]
+	}
+}
+
+// restore sets the value and cursor position to the given state. Returns
+// whether or not the cursor blink should be reset.
+func (m *Model) restore(s editState) bool {
+	m.Err = nil
+	m.value = s.value
+	m.lastEdit = editNone
+	return m.setCursor(s.pos)
+}
+
+// Undo reverts the most recent edit, if any. Consecutive typing is undone in
+// one step, as are consecutive single character deletions.
+func (m *Model) Undo() {
+	m.undo()
+}
+
+// undo reverts the most recent edit and returns whether or not the cursor
+// blink should be reset.
+func (m *Model) undo() bool {
+	if len(m.undoStack) == 0 {
+		return false
+	}
+	s := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	m.redoStack = append(m.redoStack, m.snapshot())
+	return m.restore(s)
+}
+
+// Redo reapplies the most recently undone edit, if any.
+func (m *Model) Redo() {
+	m.redo()
+}
+
+// redo reapplies the most recently undone edit and returns whether or not the
+// cursor blink should be reset.
+func (m *Model) redo() bool {
+	if len(m.redoStack) == 0 {
+		return false
+	}
+	s := m.redoStack[len(m.redoStack)-1]
+	m.redoStack = m.redoStack[:len(m.redoStack)-1]
+	m.undoStack = append(m.undoStack, m.snapshot())
+	return m.restore(s)
+}
+
+// ClearUndoHistory discards all undo and redo steps. This is useful when
+// reusing an input for an unrelated value.
+func (m *Model) ClearUndoHistory() {
+	m.undoStack = nil
+	m.redoStack = nil
+	m.lastEdit = editNone
+}
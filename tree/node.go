@@ -0,0 +1,76 @@
+package tree
+
+// Node is a node in a tree. The tree holds on to the nodes it's given, so
+// after changing them in place, such as by adding children, call the tree's
+// Refresh method to show the changes.
+type Node struct {
+	// Label is the text shown for the node. It's also what filtering matches
+	// against.
+	Label string
+
+	// Value holds arbitrary data associated with the node, such as the path
+	// of a file or a decoded JSON value.
+	Value interface{}
+
+	// Children are the node's child nodes, in the order they're shown.
+	Children []*Node
+
+	// Expanded is whether the node's children are shown.
+	Expanded bool
+
+	// Lazy marks a node whose children haven't been loaded yet. It's shown as
+	// expandable, and expanding it calls the tree's LoadChildren function.
+	Lazy bool
+
+	parent  *Node
+	loading bool
+	err     error
+}
+
+// Parent returns the node's parent, or nil for a root node. It's set when the
+// node is added to a tree.
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Loading returns whether the node's children are being loaded.
+func (n *Node) Loading() bool {
+	return n.loading
+}
+
+// Err returns the error the children of the node failed to load with, if
+// loading them failed the last time it was tried.
+func (n *Node) Err() error {
+	return n.err
+}
+
+// Expandable returns whether the node has children, or may have children
+// that haven't been loaded yet.
+func (n *Node) Expandable() bool {
+	return len(n.Children) > 0 || n.Lazy
+}
+
+// ChildrenLoadedMsg contains the children loaded for a lazy node. The command
+// returned by a tree's LoadChildren function should send one.
+type ChildrenLoadedMsg struct {
+	Node     *Node
+	Children []*Node
+
+	// Err, if set, means the children couldn't be loaded. The node is left
+	// collapsed and lazy, so expanding it again tries again, and it's marked
+	// with the error until then. The error is returned by the node's Err
+	// method.
+	Err error
+}
+
+// walk calls fn for each of the given nodes and their descendants, depth
+// first, and sets the parent of each descendant along the way. Descending into
+// a node's children stops if fn returns false.
+func walk(nodes []*Node, parent *Node, fn func(n *Node) bool) {
+	for _, n := range nodes {
+		n.parent = parent
+		if fn(n) {
+			walk(n.Children, n, fn)
+		}
+	}
+}
@@ -0,0 +1,945 @@
+// Package tree provides a Bubble Tea component for browsing hierarchical data,
+// such as file trees, JSON documents or org charts. Nodes can be expanded and
+// collapsed, their children can be loaded on demand, and the tree can be
+// filtered, in which case the ancestors of matching nodes stay visible.
+package tree
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// Indentation guides drawn in front of nested nodes.
+const (
+	guideBranch = "├─ "
+	guideLast   = "└─ "
+	guideLine   = "│  "
+	guideBlank  = "   "
+)
+
+// FilterState describes the current filtering state on the model.
+type FilterState int
+
+// Possible filter states.
+const (
+	Unfiltered    FilterState = iota // no filter set
+	Filtering                        // user is actively typing a filter term
+	FilterApplied                    // a filter term is set
+)
+
+// String returns a human-readable string of the current filter state.
+func (f FilterState) String() string {
+	return [...]string{
+		"unfiltered",
+		"filtering",
+		"filter applied",
+	}[f]
+}
+
+// KeyMap defines keybindings.
+type KeyMap struct {
+	// Keybindings used when browsing the tree.
+	LineUp      key.Binding
+	LineDown    key.Binding
+	PageUp      key.Binding
+	PageDown    key.Binding
+	GotoTop     key.Binding
+	GotoBottom  key.Binding
+	Expand      key.Binding
+	Collapse    key.Binding
+	Toggle      key.Binding
+	Filter      key.Binding
+	ClearFilter key.Binding
+
+	// Keybindings used when setting a filter.
+	CancelFilter key.Binding
+	AcceptFilter key.Binding
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	const spacebar = " "
+	return KeyMap{
+		LineUp: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		LineDown: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "b"),
+			key.WithHelp("b/pgup", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "f"),
+			key.WithHelp("f/pgdn", "page down"),
+		),
+		GotoTop: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "go to start"),
+		),
+		GotoBottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "go to end"),
+		),
+		Expand: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "expand"),
+		),
+		Collapse: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "collapse"),
+		),
+		Toggle: key.NewBinding(
+			key.WithKeys("enter", spacebar),
+			key.WithHelp("enter", "toggle"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		ClearFilter: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear filter"),
+		),
+		CancelFilter: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		AcceptFilter: key.NewBinding(
+			key.WithKeys("enter", "tab", "shift+tab", "ctrl+k", "up", "ctrl+j", "down"),
+			key.WithHelp("enter", "apply filter"),
+		),
+	}
+}
+
+// Styles contains style definitions for this tree component. By default,
+// these values are generated by DefaultStyles.
+type Styles struct {
+	// Node is the style of node labels, and Selected of the label of the node
+	// under the cursor.
+	Node     lipgloss.Style
+	Selected lipgloss.Style
+
+	// Guide is the style of the indentation guides, and Indicator of the
+	// markers in front of expandable nodes.
+	Guide     lipgloss.Style
+	Indicator lipgloss.Style
+
+	// The markers shown in front of collapsed and expanded nodes, nodes
+	// whose children are loading or failed to load, and leaf nodes.
+	Collapsed string
+	Expanded  string
+	Loading   string
+	Failed    string
+	Leaf      string
+
+	// FailedIndicator is the style of the marker in front of nodes whose
+	// children failed to load.
+	FailedIndicator lipgloss.Style
+
+	// FilterMatch is applied to the parts of labels matched by the filter.
+	FilterMatch  lipgloss.Style
+	FilterPrompt lipgloss.Style
+	FilterCursor lipgloss.Style
+	NoMatches    lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for this tree
+// component.
+func DefaultStyles() (s Styles) {
+	subduedColor := lipgloss.AdaptiveColor{Light: "#9B9B9B", Dark: "#5C5C5C"}
+
+	s.Node = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#1a1a1a", Dark: "#dddddd"})
+
+	s.Selected = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.AdaptiveColor{Light: "#EE6FF8", Dark: "#EE6FF8"})
+
+	s.Guide = lipgloss.NewStyle().Foreground(subduedColor)
+	s.Indicator = lipgloss.NewStyle().Foreground(subduedColor)
+
+	s.Collapsed = "▸ "
+	s.Expanded = "▾ "
+	s.Loading = "… "
+	s.Failed = "! "
+	s.Leaf = "  "
+
+	s.FailedIndicator = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#FF4672", Dark: "#ED567A"})
+
+	s.FilterMatch = lipgloss.NewStyle().Underline(true)
+
+	s.FilterPrompt = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#04B575", Dark: "#ECFD65"})
+
+	s.FilterCursor = lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#EE6FF8", Dark: "#EE6FF8"})
+
+	s.NoMatches = lipgloss.NewStyle().Foreground(subduedColor)
+
+	return s
+}
+
+// Option is used to set options in New. For example:
+//
+//     tree := New(
+//         WithNodes(root),
+//         WithHeight(10),
+//     )
+//
+type Option func(*Model)
+
+// WithNodes sets the root nodes of the tree.
+func WithNodes(nodes ...*Node) Option {
+	return func(m *Model) {
+		m.nodes = nodes
+	}
+}
+
+// WithLoadChildren sets the function used to load the children of lazy
+// nodes.
+func WithLoadChildren(fn func(node *Node) tea.Cmd) Option {
+	return func(m *Model) {
+		m.LoadChildren = fn
+	}
+}
+
+// WithHeight sets the height of the tree, including the filter input.
+func WithHeight(h int) Option {
+	return func(m *Model) {
+		m.height = h
+	}
+}
+
+// WithWidth sets the width of the tree.
+func WithWidth(w int) Option {
+	return func(m *Model) {
+		m.width = w
+	}
+}
+
+// WithStyles sets the tree styles.
+func WithStyles(s Styles) Option {
+	return func(m *Model) {
+		m.Styles = s
+	}
+}
+
+// WithKeyMap sets the keymap.
+func WithKeyMap(km KeyMap) Option {
+	return func(m *Model) {
+		m.KeyMap = km
+	}
+}
+
+// row is a visible node, along with the indentation guides drawn in front of
+// it.
+type row struct {
+	node   *Node
+	guides string
+}
+
+// Model is the Bubble Tea model for this tree element.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	// LoadChildren is called when a lazy node is expanded. It should return
+	// a command that loads the node's children and sends them in a
+	// ChildrenLoadedMsg.
+	LoadChildren func(node *Node) tea.Cmd
+
+	FilterInput textinput.Model
+
+	// Whether or not to respond to the mouse. The mouse must be enabled in
+	// Bubble Tea for this to work. For details, see the Bubble Tea docs.
+	MouseWheelEnabled bool
+
+	// The number of rows the mouse wheel will move the cursor. By default,
+	// this is 3.
+	MouseWheelDelta int
+
+	nodes  []*Node
+	rows   []row
+	cursor int
+	offset int
+	height int
+	width  int
+
+	filterState FilterState
+	matches     map[*Node][]int
+}
+
+// New creates a new model for the tree widget.
+func New(opts ...Option) Model {
+	filterInput := textinput.New()
+	filterInput.Prompt = "Filter: "
+
+	m := Model{
+		KeyMap:            DefaultKeyMap(),
+		Styles:            DefaultStyles(),
+		FilterInput:       filterInput,
+		MouseWheelEnabled: true,
+		MouseWheelDelta:   3, //nolint:gomnd
+
+		height: 20, //nolint:gomnd
+	}
+
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	m.FilterInput.PromptStyle = m.Styles.FilterPrompt
+	m.FilterInput.CursorStyle = m.Styles.FilterCursor
+	m.Refresh()
+	m.updateKeybindings()
+	return m
+}
+
+// Nodes returns the root nodes of the tree.
+func (m Model) Nodes() []*Node {
+	return m.nodes
+}
+
+// SetNodes sets the root nodes of the tree.
+func (m *Model) SetNodes(nodes ...*Node) {
+	m.nodes = nodes
+	m.Refresh()
+}
+
+// Refresh updates the tree after its nodes have been changed in place. The
+// cursor stays on the selected node if it's still visible, or otherwise moves
+// to its closest visible ancestor.
+func (m *Model) Refresh() {
+	selected := m.SelectedNode()
+
+	// Set the parents of nodes that have been added since the last refresh.
+	m.walk(func(*Node) bool { return true })
+	if m.filterState != Unfiltered {
+		m.matchNodes()
+	}
+
+	m.rows = nil
+	m.flatten(m.nodes, "", true)
+
+	for n := selected; n != nil; n = n.parent {
+		if i := m.indexOf(n); i >= 0 {
+			m.cursor = i
+			break
+		}
+	}
+	m.updateOffset()
+}
+
+// SetWidth sets the width of the tree.
+func (m *Model) SetWidth(w int) {
+	m.width = w
+}
+
+// SetHeight sets the height of the tree, including the filter input.
+func (m *Model) SetHeight(h int) {
+	m.height = h
+	m.updateOffset()
+}
+
+// Height returns the height of the tree, including the filter input.
+func (m Model) Height() int {
+	return m.height
+}
+
+// Width returns the width of the tree.
+func (m Model) Width() int {
+	return m.width
+}
+
+// VisibleNodes returns the nodes currently shown in the tree, in order.
+func (m Model) VisibleNodes() []*Node {
+	nodes := make([]*Node, len(m.rows))
+	for i, r := range m.rows {
+		nodes[i] = r.node
+	}
+	return nodes
+}
+
+// Cursor returns the index of the selected node among the visible nodes.
+func (m Model) Cursor() int {
+	return m.cursor
+}
+
+// SetCursor moves the cursor to the visible node at the given index. The view
+// scrolls to keep the cursor visible.
+func (m *Model) SetCursor(n int) {
+	m.cursor = clamp(n, 0, len(m.rows)-1)
+	m.updateOffset()
+}
+
+// SelectedNode returns the node under the cursor, or nil if the tree is empty.
+func (m Model) SelectedNode() *Node {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return m.rows[m.cursor].node
+}
+
+// Select moves the cursor to the given node, expanding its ancestors so that
+// it's visible. Nodes hidden by the filter can't be selected.
+func (m *Model) Select(node *Node) {
+	for n := node.parent; n != nil; n = n.parent {
+		n.Expanded = true
+	}
+	m.Refresh()
+	if i := m.indexOf(node); i >= 0 {
+		m.SetCursor(i)
+	}
+}
+
+// MoveUp moves the cursor up by any number of nodes.
+func (m *Model) MoveUp(n int) {
+	m.SetCursor(m.cursor - n)
+}
+
+// MoveDown moves the cursor down by any number of nodes.
+func (m *Model) MoveDown(n int) {
+	m.SetCursor(m.cursor + n)
+}
+
+// GotoTop moves the cursor to the first node.
+func (m *Model) GotoTop() {
+	m.SetCursor(0)
+}
+
+// GotoBottom moves the cursor to the last visible node.
+func (m *Model) GotoBottom() {
+	m.SetCursor(len(m.rows) - 1)
+}
+
+// Expand expands the given node. If it's lazy, this returns a command that
+// loads its children, and the node is expanded once they've loaded.
+func (m *Model) Expand(node *Node) tea.Cmd {
+	if node.Lazy {
+		if node.loading || m.LoadChildren == nil {
+			return nil
+		}
+		node.loading = true
+		node.err = nil
+		return m.LoadChildren(node)
+	}
+	node.Expanded = true
+	m.Refresh()
+	return nil
+}
+
+// Collapse collapses the given node.
+func (m *Model) Collapse(node *Node) {
+	node.Expanded = false
+	m.Refresh()
+}
+
+// Toggle expands the given node if it's collapsed and collapses it otherwise.
+// This returns a command if the node's children need to be loaded.
+func (m *Model) Toggle(node *Node) tea.Cmd {
+	if node.Expanded {
+		m.Collapse(node)
+		return nil
+	}
+	return m.Expand(node)
+}
+
+// ExpandAll expands every node whose children have been loaded.
+func (m *Model) ExpandAll() {
+	m.walk(func(n *Node) bool {
+		n.Expanded = len(n.Children) > 0
+		return true
+	})
+	m.Refresh()
+}
+
+// CollapseAll collapses every node.
+func (m *Model) CollapseAll() {
+	m.walk(func(n *Node) bool {
+		n.Expanded = false
+		return true
+	})
+	m.Refresh()
+}
+
+// FilterState returns the current filter state.
+func (m Model) FilterState() FilterState {
+	return m.filterState
+}
+
+// FilterValue returns the current value of the filter.
+func (m Model) FilterValue() string {
+	return m.FilterInput.Value()
+}
+
+// SetFilter filters the tree by the given term, showing the nodes that match
+// it and their ancestors. Only the children of lazy nodes that have been
+// loaded are matched. An empty term resets the filter.
+func (m *Model) SetFilter(term string) {
+	if term == "" {
+		m.ResetFilter()
+		return
+	}
+	m.FilterInput.SetValue(term)
+	m.FilterInput.Blur()
+	m.filterState = FilterApplied
+	m.Refresh()
+	m.updateKeybindings()
+}
+
+// ResetFilter clears the filter, if any.
+func (m *Model) ResetFilter() {
+	m.FilterInput.Reset()
+	m.FilterInput.Blur()
+	m.filterState = Unfiltered
+	m.matches = nil
+	m.Refresh()
+	m.updateKeybindings()
+}
+
+// MatchesForNode returns rune positions of the node's label matched by the
+// filter, if any.
+func (m Model) MatchesForNode(node *Node) []int {
+	return m.matches[node]
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(ChildrenLoadedMsg); ok {
+		m.handleChildrenLoaded(msg)
+		return m, nil
+	}
+
+	if m.filterState == Filtering {
+		return m, m.handleFiltering(msg)
+	}
+
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		cmd = m.handleBrowsing(msg)
+
+	case tea.MouseMsg:
+		cmd = m.handleMouse(msg)
+	}
+
+	return m, cmd
+}
+
+// Updates for when a user is browsing the tree.
+func (m *Model) handleBrowsing(msg tea.KeyMsg) tea.Cmd {
+	height := m.rowsHeight()
+
+	switch {
+	case key.Matches(msg, m.KeyMap.LineUp):
+		m.MoveUp(1)
+	case key.Matches(msg, m.KeyMap.LineDown):
+		m.MoveDown(1)
+	case key.Matches(msg, m.KeyMap.PageUp):
+		m.MoveUp(height)
+	case key.Matches(msg, m.KeyMap.PageDown):
+		m.MoveDown(height)
+	case key.Matches(msg, m.KeyMap.GotoTop):
+		m.GotoTop()
+	case key.Matches(msg, m.KeyMap.GotoBottom):
+		m.GotoBottom()
+
+	case key.Matches(msg, m.KeyMap.ClearFilter):
+		m.ResetFilter()
+
+	case key.Matches(msg, m.KeyMap.Filter):
+		m.filterState = Filtering
+		m.FilterInput.CursorEnd()
+		m.FilterInput.Focus()
+		m.updateKeybindings()
+		return textinput.Blink
+
+	case key.Matches(msg, m.KeyMap.Expand):
+		node := m.SelectedNode()
+		switch {
+		case node == nil:
+		case m.shownExpanded(node) && len(node.Children) > 0:
+			// Already expanded, so step into it.
+			m.MoveDown(1)
+		default:
+			return m.Expand(node)
+		}
+
+	case key.Matches(msg, m.KeyMap.Collapse):
+		node := m.SelectedNode()
+		switch {
+		case node == nil:
+		case node.Expanded && !m.forcedOpen(node):
+			m.Collapse(node)
+		case node.parent != nil:
+			m.Select(node.parent)
+		}
+
+	case key.Matches(msg, m.KeyMap.Toggle):
+		if node := m.SelectedNode(); node != nil && node.Expandable() {
+			return m.Toggle(node)
+		}
+	}
+	return nil
+}
+
+// Updates for when a user is typing a filter term.
+func (m *Model) handleFiltering(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.KeyMap.CancelFilter):
+			m.ResetFilter()
+			return nil
+
+		case key.Matches(msg, m.KeyMap.AcceptFilter):
+			m.SetFilter(m.FilterInput.Value())
+			return nil
+		}
+	}
+
+	newFilterInputModel, cmd := m.FilterInput.Update(msg)
+	filterChanged := m.FilterInput.Value() != newFilterInputModel.Value()
+	m.FilterInput = newFilterInputModel
+
+	// If the filter term has changed, move to the first match
+	if filterChanged {
+		m.Refresh()
+		for i, r := range m.rows {
+			if len(m.matches[r.node]) > 0 {
+				m.SetCursor(i)
+				break
+			}
+		}
+	}
+	return cmd
+}
+
+// handleMouse handles mouse events while browsing. The wheel moves the cursor
+// and clicking a node selects it, or toggles it if it's already selected.
+// Events are expected to be relative to the top left corner of the tree.
+func (m *Model) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if !m.MouseWheelEnabled {
+		return nil
+	}
+
+	switch msg.Type {
+	case tea.MouseWheelUp:
+		m.MoveUp(m.MouseWheelDelta)
+	case tea.MouseWheelDown:
+		m.MoveDown(m.MouseWheelDelta)
+	case tea.MouseLeft:
+		y := msg.Y - m.filterHeight()
+		if y < 0 || y >= m.rowsHeight() || m.offset+y >= len(m.rows) {
+			return nil
+		}
+		if index := m.offset + y; index != m.cursor {
+			m.SetCursor(index)
+			return nil
+		}
+		if node := m.SelectedNode(); node.Expandable() {
+			return m.Toggle(node)
+		}
+	}
+	return nil
+}
+
+// handleChildrenLoaded adds loaded children to their node and expands it. If
+// they failed to load, the node is marked with the error instead.
+func (m *Model) handleChildrenLoaded(msg ChildrenLoadedMsg) {
+	node := msg.Node
+	if node == nil || !node.loading {
+		return
+	}
+	node.loading = false
+	if msg.Err != nil {
+		node.err = msg.Err
+		return
+	}
+
+	node.Children = msg.Children
+	node.Lazy = false
+	node.Expanded = true
+	m.Refresh()
+}
+
+// updateKeybindings enables the keybindings that apply in the current filter
+// state.
+func (m *Model) updateKeybindings() {
+	filtering := m.filterState == Filtering
+
+	m.KeyMap.LineUp.SetEnabled(!filtering)
+	m.KeyMap.LineDown.SetEnabled(!filtering)
+	m.KeyMap.PageUp.SetEnabled(!filtering)
+	m.KeyMap.PageDown.SetEnabled(!filtering)
+	m.KeyMap.GotoTop.SetEnabled(!filtering)
+	m.KeyMap.GotoBottom.SetEnabled(!filtering)
+	m.KeyMap.Expand.SetEnabled(!filtering)
+	m.KeyMap.Collapse.SetEnabled(!filtering)
+	m.KeyMap.Toggle.SetEnabled(!filtering)
+	m.KeyMap.Filter.SetEnabled(!filtering)
+	m.KeyMap.ClearFilter.SetEnabled(m.filterState == FilterApplied)
+
+	m.KeyMap.CancelFilter.SetEnabled(filtering)
+	m.KeyMap.AcceptFilter.SetEnabled(filtering)
+}
+
+// ShortHelp returns bindings to show in the abbreviated help view. It's part
+// of the help.KeyMap interface.
+func (m Model) ShortHelp() []key.Binding {
+	if m.filterState == Filtering {
+		return []key.Binding{m.KeyMap.AcceptFilter, m.KeyMap.CancelFilter}
+	}
+	return []key.Binding{
+		m.KeyMap.LineUp,
+		m.KeyMap.LineDown,
+		m.KeyMap.Collapse,
+		m.KeyMap.Expand,
+		m.KeyMap.Filter,
+		m.KeyMap.ClearFilter,
+	}
+}
+
+// FullHelp returns bindings to show the full help view. It's part of the
+// help.KeyMap interface.
+func (m Model) FullHelp() [][]key.Binding {
+	if m.filterState == Filtering {
+		return [][]key.Binding{{m.KeyMap.AcceptFilter, m.KeyMap.CancelFilter}}
+	}
+	return [][]key.Binding{
+		{m.KeyMap.LineUp, m.KeyMap.LineDown, m.KeyMap.GotoTop, m.KeyMap.GotoBottom},
+		{m.KeyMap.PageUp, m.KeyMap.PageDown},
+		{m.KeyMap.Collapse, m.KeyMap.Expand, m.KeyMap.Toggle},
+		{m.KeyMap.Filter, m.KeyMap.ClearFilter},
+	}
+}
+
+// View renders the component.
+func (m Model) View() string {
+	var b strings.Builder
+
+	if m.filterState != Unfiltered {
+		b.WriteString(m.truncateWidth(m.FilterInput.View()))
+		if m.rowsHeight() > 0 {
+			b.WriteString("\n")
+		}
+		if len(m.rows) == 0 {
+			b.WriteString(m.Styles.NoMatches.Render("No matches"))
+			return b.String()
+		}
+	}
+
+	end := min(len(m.rows), m.offset+m.rowsHeight())
+	for i := m.offset; i < end; i++ {
+		if i > m.offset {
+			b.WriteString("\n")
+		}
+		b.WriteString(m.renderRow(i))
+	}
+	return b.String()
+}
+
+// renderRow renders the visible node at the given index.
+func (m Model) renderRow(index int) string {
+	r := m.rows[index]
+	node := r.node
+
+	var indicator string
+	indicatorStyle := m.Styles.Indicator
+	switch {
+	case node.loading:
+		indicator = m.Styles.Loading
+	case node.err != nil:
+		indicator = m.Styles.Failed
+		indicatorStyle = m.Styles.FailedIndicator
+	case !node.Expandable():
+		indicator = m.Styles.Leaf
+	case m.shownExpanded(node):
+		indicator = m.Styles.Expanded
+	default:
+		indicator = m.Styles.Collapsed
+	}
+
+	style := m.Styles.Node
+	if index == m.cursor {
+		style = m.Styles.Selected
+	}
+	label := strings.ReplaceAll(node.Label, "\n", " ")
+	if matched := m.matches[node]; len(matched) > 0 {
+		label = lipgloss.StyleRunes(label, matched, style.Copy().Inherit(m.Styles.FilterMatch), style)
+	} else {
+		label = style.Render(label)
+	}
+
+	return m.truncateWidth(m.Styles.Guide.Render(r.guides) + indicatorStyle.Render(indicator) + label)
+}
+
+// flatten appends the rows for the given sibling nodes and their visible
+// descendants. prefix holds the guides inherited from the nodes' ancestors.
+func (m *Model) flatten(nodes []*Node, prefix string, root bool) {
+	visible := nodes
+	if m.filterState != Unfiltered {
+		visible = make([]*Node, 0, len(nodes))
+		for _, n := range nodes {
+			if _, ok := m.matches[n]; ok || m.forcedOpen(n) {
+				visible = append(visible, n)
+			}
+		}
+	}
+
+	for i, n := range visible {
+		last := i == len(visible)-1
+		guides, childPrefix := prefix, prefix
+		if !root {
+			if last {
+				guides += guideLast
+				childPrefix += guideBlank
+			} else {
+				guides += guideBranch
+				childPrefix += guideLine
+			}
+		}
+		m.rows = append(m.rows, row{node: n, guides: guides})
+		if m.shownExpanded(n) {
+			m.flatten(n.Children, childPrefix, false)
+		}
+	}
+}
+
+// matchNodes matches the labels of all loaded nodes against the filter term.
+// Matching nodes map to their matched rune positions, and their ancestors are
+// included with no positions so that they stay visible.
+func (m *Model) matchNodes() {
+	var (
+		nodes   []*Node
+		targets []string
+	)
+	m.walk(func(n *Node) bool {
+		nodes = append(nodes, n)
+		targets = append(targets, n.Label)
+		return true
+	})
+
+	m.matches = make(map[*Node][]int)
+	term := m.FilterInput.Value()
+	if term == "" {
+		// Everything matches while the filter is empty.
+		for _, n := range nodes {
+			m.matches[n] = nil
+		}
+		return
+	}
+	for _, match := range fuzzy.Find(term, targets) {
+		n := nodes[match.Index]
+		m.matches[n] = match.MatchedIndexes
+		for p := n.parent; p != nil; p = p.parent {
+			if _, ok := m.matches[p]; ok {
+				break
+			}
+			m.matches[p] = nil
+		}
+	}
+}
+
+// shownExpanded returns whether the node's children are shown. While the tree
+// is filtered, the ancestors of matching nodes are always expanded.
+func (m Model) shownExpanded(n *Node) bool {
+	return n.Expanded || m.forcedOpen(n)
+}
+
+// forcedOpen returns whether the node is expanded by the filter because some
+// of its descendants match.
+func (m Model) forcedOpen(n *Node) bool {
+	if m.filterState == Unfiltered || m.FilterInput.Value() == "" {
+		return false
+	}
+	for _, c := range n.Children {
+		if _, ok := m.matches[c]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// walk calls fn for every loaded node in the tree, depth first.
+func (m *Model) walk(fn func(n *Node) bool) {
+	walk(m.nodes, nil, fn)
+}
+
+// indexOf returns the index of the given node among the visible nodes, or -1.
+func (m Model) indexOf(node *Node) int {
+	for i, r := range m.rows {
+		if r.node == node {
+			return i
+		}
+	}
+	return -1
+}
+
+// filterHeight returns the height of the filter input, which is shown while
+// the tree is filtered.
+func (m Model) filterHeight() int {
+	if m.filterState == Unfiltered {
+		return 0
+	}
+	return 1
+}
+
+// rowsHeight returns the number of rows that fit beneath the filter input.
+func (m Model) rowsHeight() int {
+	return max(0, m.height-m.filterHeight())
+}
+
+// updateOffset keeps the cursor in bounds and scrolls so that it's visible.
+func (m *Model) updateOffset() {
+	m.cursor = clamp(m.cursor, 0, len(m.rows)-1)
+	height := m.rowsHeight()
+
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+	m.offset = clamp(m.offset, 0, len(m.rows)-height)
+}
+
+// truncateWidth cuts the given line to the width of the tree, if a width is
+// set.
+func (m Model) truncateWidth(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(s)
+}
+
+// clamp restricts v to the range [low, high]. If the range is empty, as is the
+// case for an empty tree, low is returned.
+func clamp(v, low, high int) int {
+	if high < low {
+		return low
+	}
+	return min(high, max(low, v))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
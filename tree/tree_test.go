@@ -0,0 +1,170 @@
+package tree
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestTree returns a tree with a collapsed node with children and a lazy
+// node whose children are loaded by the given function.
+func newTestTree(load func(node *Node) tea.Cmd) Model {
+	fruits := &Node{
+		Label: "fruits",
+		Children: []*Node{
+			{Label: "apple"},
+			{Label: "banana", Children: []*Node{{Label: "cavendish"}}},
+		},
+	}
+	vegetables := &Node{Label: "vegetables", Lazy: true}
+	return New(WithNodes(fruits, vegetables), WithLoadChildren(load))
+}
+
+// loadChildren returns a LoadChildren function that loads the given children,
+// or fails with the given error.
+func loadChildren(children []*Node, err error) func(node *Node) tea.Cmd {
+	return func(node *Node) tea.Cmd {
+		return func() tea.Msg {
+			return ChildrenLoadedMsg{Node: node, Children: children, Err: err}
+		}
+	}
+}
+
+func labels(m Model) []string {
+	var labels []string
+	for _, n := range m.VisibleNodes() {
+		labels = append(labels, n.Label)
+	}
+	return labels
+}
+
+func assertLabels(t *testing.T, m Model, want ...string) {
+	t.Helper()
+	if got := labels(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("visible nodes = %v, want %v", got, want)
+	}
+}
+
+func TestExpandCollapse(t *testing.T) {
+	m := newTestTree(nil)
+	assertLabels(t, m, "fruits", "vegetables")
+
+	fruits := m.Nodes()[0]
+	m.Expand(fruits)
+	assertLabels(t, m, "fruits", "apple", "banana", "vegetables")
+
+	// Selecting a node expands its ancestors.
+	cavendish := fruits.Children[1].Children[0]
+	m.Select(cavendish)
+	assertLabels(t, m, "fruits", "apple", "banana", "cavendish", "vegetables")
+	if m.SelectedNode() != cavendish {
+		t.Fatalf("selected %q, want cavendish", m.SelectedNode().Label)
+	}
+
+	// Collapsing a node keeps the cursor on it.
+	m.Collapse(fruits)
+	assertLabels(t, m, "fruits", "vegetables")
+	if m.SelectedNode() != fruits {
+		t.Errorf("selected %q after collapsing its ancestor, want fruits", m.SelectedNode().Label)
+	}
+
+	m.ExpandAll()
+	assertLabels(t, m, "fruits", "apple", "banana", "cavendish", "vegetables")
+	m.CollapseAll()
+	assertLabels(t, m, "fruits", "vegetables")
+}
+
+func TestCollapseKey(t *testing.T) {
+	m := newTestTree(nil)
+	fruits := m.Nodes()[0]
+	m.Select(fruits.Children[0])
+
+	// The first press goes to the parent, and the second collapses it.
+	left := tea.KeyMsg{Type: tea.KeyLeft}
+	m, _ = m.Update(left)
+	if m.SelectedNode() != fruits {
+		t.Fatalf("selected %q, want fruits", m.SelectedNode().Label)
+	}
+	m, _ = m.Update(left)
+	if fruits.Expanded {
+		t.Error("fruits wasn't collapsed")
+	}
+	assertLabels(t, m, "fruits", "vegetables")
+}
+
+func TestLazyLoading(t *testing.T) {
+	m := newTestTree(loadChildren([]*Node{{Label: "carrot"}, {Label: "leek"}}, nil))
+	vegetables := m.Nodes()[1]
+
+	cmd := m.Expand(vegetables)
+	if cmd == nil {
+		t.Fatal("expanding a lazy node returned no command")
+	}
+	if !vegetables.Loading() {
+		t.Error("node isn't loading")
+	}
+	if m.Expand(vegetables) != nil {
+		t.Error("expanding a loading node loaded it again")
+	}
+
+	m, _ = m.Update(cmd())
+	if vegetables.Loading() || vegetables.Lazy || !vegetables.Expanded {
+		t.Errorf("node wasn't expanded after loading: %+v", vegetables)
+	}
+	assertLabels(t, m, "fruits", "vegetables", "carrot", "leek")
+	if p := vegetables.Children[0].Parent(); p != vegetables {
+		t.Errorf("parent of loaded child = %v, want vegetables", p)
+	}
+}
+
+func TestLazyLoadingError(t *testing.T) {
+	loadErr := errors.New("permission denied")
+	m := newTestTree(loadChildren(nil, loadErr))
+	vegetables := m.Nodes()[1]
+
+	m, _ = m.Update(m.Expand(vegetables)())
+	if vegetables.Err() != loadErr {
+		t.Fatalf("Err() = %v, want %v", vegetables.Err(), loadErr)
+	}
+	if vegetables.Loading() || !vegetables.Lazy || vegetables.Expanded {
+		t.Errorf("node changed after failing to load: %+v", vegetables)
+	}
+	if !strings.Contains(m.View(), "! vegetables") {
+		t.Errorf("the error isn't shown:\n%s", m.View())
+	}
+
+	// Trying again clears the error.
+	m.LoadChildren = loadChildren([]*Node{{Label: "leek"}}, nil)
+	cmd := m.Expand(vegetables)
+	if vegetables.Err() != nil {
+		t.Errorf("Err() = %v while trying again", vegetables.Err())
+	}
+	m, _ = m.Update(cmd())
+	assertLabels(t, m, "fruits", "vegetables", "leek")
+}
+
+func TestFilterKeepsAncestors(t *testing.T) {
+	m := newTestTree(nil)
+	fruits := m.Nodes()[0]
+
+	m.SetFilter("cavendish")
+	assertLabels(t, m, "fruits", "banana", "cavendish")
+	if fruits.Expanded {
+		t.Error("filtering expanded the ancestors of the match")
+	}
+	if len(m.MatchesForNode(fruits)) != 0 {
+		t.Error("an ancestor of the match was highlighted")
+	}
+	if len(m.MatchesForNode(fruits.Children[1].Children[0])) == 0 {
+		t.Error("the match wasn't highlighted")
+	}
+
+	m.SetFilter("zzz")
+	assertLabels(t, m)
+
+	m.ResetFilter()
+	assertLabels(t, m, "fruits", "vegetables")
+}